@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"vbom.ml/util/sortorder"
@@ -18,11 +21,13 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func getFiltersFromQuery(filterStrings []string) ([]filters.FilterT, bool) {
+// getFiltersFromQuery parses all filters, relative filters like @age will
+// compare alerts with now, unless it's zero
+func getFiltersFromQuery(filterStrings []string, now time.Time) ([]filters.FilterT, bool) {
 	validFilters := false
 	matchFilters := []filters.FilterT{}
 	for _, filterExpression := range filterStrings {
-		f := filters.NewFilter(filterExpression)
+		f := filters.NewFilterAt(filterExpression, now)
 		if f.GetIsValid() {
 			validFilters = true
 		}
@@ -31,6 +36,84 @@ func getFiltersFromQuery(filterStrings []string) ([]filters.FilterT, bool) {
 	return matchFilters, validFilters
 }
 
+// matchGroupFilters returns true if given alert group matches all valid
+// group filters
+func matchGroupFilters(matchFilters []filters.FilterT, group *models.AlertGroup) bool {
+	isMatch := true
+	for _, filter := range matchFilters {
+		if gf, ok := filter.(filters.GroupFilterT); ok && gf.GetIsValid() {
+			if !gf.MatchGroup(group) {
+				isMatch = false
+			}
+		}
+	}
+	return isMatch
+}
+
+// limitAlerts applies all valid alert limit filters to given list of alerts,
+// matches is the number of alerts that were already accepted, it must be
+// called only after group filters were checked
+func limitAlerts(matchFilters []filters.FilterT, alerts []models.Alert, matches int) []models.Alert {
+	limited := []models.Alert{}
+	for _, alert := range alerts {
+		alert := alert // scopelint pin
+		isMatch := true
+		for _, filter := range matchFilters {
+			if al, ok := filter.(filters.AlertLimitT); ok && al.GetIsValid() {
+				if !al.Match(&alert, matches+len(limited)) {
+					isMatch = false
+				}
+			}
+		}
+		if isMatch {
+			limited = append(limited, alert)
+		}
+	}
+	return limited
+}
+
+// limitGroup applies all valid group limit filters to given alert group
+func limitGroup(matchFilters []filters.FilterT, group *models.AlertGroup) {
+	for _, filter := range matchFilters {
+		if gl, ok := filter.(filters.GroupLimitT); ok && gl.GetIsValid() {
+			gl.LimitGroup(group)
+		}
+	}
+}
+
+// resolveInhibitors returns labels of every alert that is inhibiting any of
+// the alerts in groups, keyed by the alert fingerprint, fingerprints that
+// don't match any known alert are skipped
+func resolveInhibitors(allGroups []models.AlertGroup, groups map[string]models.APIAlertGroup) map[string]map[string]string {
+	inhibitors := map[string]map[string]string{}
+	for _, ag := range groups {
+		for _, alert := range ag.Alerts {
+			for _, am := range alert.Alertmanager {
+				for _, fingerprint := range am.InhibitedBy {
+					inhibitors[fingerprint] = nil
+				}
+			}
+		}
+	}
+	if len(inhibitors) == 0 {
+		return inhibitors
+	}
+
+	for _, ag := range allGroups {
+		for _, alert := range ag.Alerts {
+			if labels, found := inhibitors[alert.Fingerprint]; found && labels == nil {
+				inhibitors[alert.Fingerprint] = alert.Labels
+			}
+		}
+	}
+	for fingerprint, labels := range inhibitors {
+		if labels == nil {
+			delete(inhibitors, fingerprint)
+		}
+	}
+	return inhibitors
+}
+
 func countLabel(countStore map[string]map[string]int, key string, val string) {
 	if _, found := countStore[key]; !found {
 		countStore[key] = make(map[string]int)
@@ -109,14 +192,23 @@ func getUpstreams() models.AlertmanagerAPISummary {
 		}
 
 		u := models.AlertmanagerAPIStatus{
-			Name:           upstream.Name,
-			URI:            upstream.InternalURI(),
-			PublicURI:      upstream.PublicURI(),
-			Headers:        map[string]string{},
-			Error:          upstream.Error(),
-			Version:        upstream.Version(),
-			Cluster:        upstream.ClusterID(),
-			ClusterMembers: members,
+			Name:              upstream.Name,
+			URI:               upstream.InternalURI(),
+			PublicURI:         upstream.PublicURI(),
+			Headers:           map[string]string{},
+			Error:             upstream.Error(),
+			Version:           upstream.Version(),
+			VersionSource:     upstream.VersionSource(),
+			VersionProbeError: upstream.VersionProbeError(),
+			Cluster:           upstream.ClusterID(),
+			ClusterMembers:    members,
+			Stale:             upstream.IsStale(),
+			LastPull:          upstream.LastPull(),
+			CircuitBreaker: models.CircuitBreakerStatus{
+				State:               upstream.BreakerState(),
+				ConsecutiveFailures: upstream.ConsecutiveFailures(),
+				RetryAt:             upstream.BreakerRetryAt(),
+			},
 		}
 		if !upstream.ProxyRequests {
 			for k, v := range uri.HeadersForBasicAuth(upstream.URI) {
@@ -164,23 +256,61 @@ func getGroupLabel(group *models.APIAlertGroup, label string) string {
 }
 
 func sortByStartsAt(i, j int, groups []models.APIAlertGroup, sortReverse bool) bool {
+	if groups[i].LatestStartsAt.Equal(groups[j].LatestStartsAt) {
+		// use group ID so groups with the same timestamp are always returned in
+		// the same order, otherwise pagination could skip some groups
+		return groups[i].ID > groups[j].ID
+	}
 	if sortReverse {
 		return groups[i].LatestStartsAt.After(groups[j].LatestStartsAt)
 	}
 	return groups[i].LatestStartsAt.Before(groups[j].LatestStartsAt)
 }
 
-func sortAlertGroups(c *gin.Context, groupsMap map[string]models.APIAlertGroup) []models.APIAlertGroup {
+// gridSettings returns default sort settings, if a view is passed then any
+// sort setting set on that view will be used instead of the grid config
+func gridSettings(view *config.ViewConfig) models.GridSettings {
+	settings := models.GridSettings{
+		Order:   config.Config.Grid.Sorting.Order,
+		Reverse: config.Config.Grid.Sorting.Reverse,
+		Label:   config.Config.Grid.Sorting.Label,
+	}
+	if view != nil {
+		if view.Sorting.Order != "" {
+			settings.Order = view.Sorting.Order
+		}
+		if view.Sorting.Reverse != nil {
+			settings.Reverse = *view.Sorting.Reverse
+		}
+		if view.Sorting.Label != "" {
+			settings.Label = view.Sorting.Label
+		}
+	}
+	return settings
+}
+
+// findView returns the view with given name, or nil if there's no such view
+func findView(name string) *config.ViewConfig {
+	for _, view := range config.Config.NamedViews() {
+		if view.Name == name {
+			view := view // scopelint pin
+			return &view
+		}
+	}
+	return nil
+}
+
+func sortAlertGroups(c *gin.Context, groupsMap map[string]models.APIAlertGroup, defaults models.GridSettings) []models.APIAlertGroup {
 	groups := make([]models.APIAlertGroup, 0, len(groupsMap))
 
 	sortOrder, found := c.GetQuery("sortOrder")
 	if !found || sortOrder == "" {
-		sortOrder = config.Config.Grid.Sorting.Order
+		sortOrder = defaults.Order
 	}
 
 	sortReverse, found := c.GetQuery("sortReverse")
 	if !found || (sortReverse != "0" && sortReverse != "1") {
-		if config.Config.Grid.Sorting.Reverse {
+		if defaults.Reverse {
 			sortReverse = "1"
 		} else {
 			sortReverse = "0"
@@ -189,7 +319,7 @@ func sortAlertGroups(c *gin.Context, groupsMap map[string]models.APIAlertGroup)
 
 	sortLabel, found := c.GetQuery("sortLabel")
 	if !found || sortLabel == "" {
-		sortLabel = config.Config.Grid.Sorting.Label
+		sortLabel = defaults.Label
 	}
 
 	for _, g := range groupsMap {
@@ -241,3 +371,325 @@ func sortAlertGroups(c *gin.Context, groupsMap map[string]models.APIAlertGroup)
 
 	return groups
 }
+
+// optional sections of alerts response, fields= query argument can be used
+// to only include some of them
+var alertsResponseFields = []string{"annotations", "colors", "counters", "inhibitors", "silences"}
+
+// splitQueryArray returns all values passed using given query argument, each
+// value can also be a comma separated list
+func splitQueryArray(values []string) []string {
+	items := []string{}
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// getFieldsFromQuery returns the list of optional response sections that were
+// requested using fields= query argument, all sections are returned if it's
+// not set
+func getFieldsFromQuery(c *gin.Context) ([]string, error) {
+	values, found := c.GetQueryArray("fields")
+	if !found {
+		return alertsResponseFields, nil
+	}
+
+	fields := splitQueryArray(values)
+	for _, field := range fields {
+		if !slices.StringInSlice(alertsResponseFields, field) {
+			return nil, fmt.Errorf("unknown field '%s', valid fields: %s", field, strings.Join(alertsResponseFields, ", "))
+		}
+	}
+	return fields, nil
+}
+
+// getPageFromQuery returns offset and limit values used to paginate alert
+// groups, limit is zero if all groups should be returned
+func getPageFromQuery(c *gin.Context) (int, int, error) {
+	page := []int{0, 0}
+	for i, name := range []string{"offset", "limit"} {
+		value, found := c.GetQuery(name)
+		if !found || value == "" {
+			continue
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil || v < 0 {
+			return 0, 0, fmt.Errorf("invalid %s value '%s', it must be a non-negative integer", name, value)
+		}
+		page[i] = v
+	}
+	return page[0], page[1], nil
+}
+
+// paginateAlertGroups returns sorted alert groups for the requested page
+func paginateAlertGroups(groups []models.APIAlertGroup, offset, limit int) []models.APIAlertGroup {
+	if offset >= len(groups) {
+		return []models.APIAlertGroup{}
+	}
+	groups = groups[offset:]
+	if limit > 0 && limit < len(groups) {
+		groups = groups[:limit]
+	}
+	return groups
+}
+
+// filterSilences returns only those silences that are silencing alerts in
+// given groups, so paginated responses don't include silences for all pages
+func filterSilences(silences map[string]map[string]models.Silence, groups []models.APIAlertGroup) map[string]map[string]models.Silence {
+	filtered := map[string]map[string]models.Silence{}
+	for cluster := range silences {
+		filtered[cluster] = map[string]models.Silence{}
+	}
+	for _, ag := range groups {
+		for _, alert := range ag.Alerts {
+			for _, am := range alert.Alertmanager {
+				for _, silenceID := range am.SilencedBy {
+					if silence, found := silences[am.Cluster][silenceID]; found {
+						filtered[am.Cluster][silenceID] = silence
+					}
+				}
+			}
+		}
+	}
+	return filtered
+}
+
+// removeSkippedFields replaces all optional sections of the response that
+// weren't requested with empty values
+func removeSkippedFields(resp *models.AlertsResponse, fields []string) {
+	if !slices.StringInSlice(fields, "annotations") {
+		for i := range resp.AlertGroups {
+			resp.AlertGroups[i].Shared.Annotations = models.Annotations{}
+			for j := range resp.AlertGroups[i].Alerts {
+				resp.AlertGroups[i].Alerts[j].Annotations = models.Annotations{}
+			}
+		}
+	}
+	if !slices.StringInSlice(fields, "colors") {
+		resp.Colors = models.LabelsColorMap{}
+	}
+	if !slices.StringInSlice(fields, "counters") {
+		resp.Counters = models.LabelNameStatsList{}
+	}
+	if !slices.StringInSlice(fields, "inhibitors") {
+		resp.Inhibitors = map[string]map[string]string{}
+	}
+	if !slices.StringInSlice(fields, "silences") {
+		resp.Silences = map[string]map[string]models.Silence{}
+	}
+}
+
+// getGroupByFromQuery returns the list of labels alerts should be regrouped
+// by, grid.groupBy from the config is used if groupBy= query argument isn't
+// set, an empty list means that alerts are grouped as in Alertmanager
+func getGroupByFromQuery(c *gin.Context) ([]string, error) {
+	values, found := c.GetQueryArray("groupBy")
+	if !found {
+		return config.Config.Grid.GroupBy, nil
+	}
+
+	groupBy := splitQueryArray(values)
+	for _, label := range groupBy {
+		if strings.HasPrefix(label, "@") && !slices.StringInSlice(config.GroupBySpecialLabels, label) {
+			return nil, fmt.Errorf("invalid groupBy label '%s', allowed special labels: %s", label, strings.Join(config.GroupBySpecialLabels, ", "))
+		}
+	}
+	return groupBy, nil
+}
+
+// alertGroupKey is a partial alert group used when regrouping alerts, keys
+// are values of all labels used for grouping, including special labels
+type alertGroupKey struct {
+	keys  map[string]string
+	alert models.Alert
+}
+
+// withKey returns a copy with given key set, it will only keep Alertmanager
+// instances passed in ams, unless it's nil
+func (gk alertGroupKey) withKey(name, value string, ams []models.AlertmanagerInstance) alertGroupKey {
+	keys := make(map[string]string, len(gk.keys)+1)
+	for k, v := range gk.keys {
+		keys[k] = v
+	}
+	keys[name] = value
+
+	alert := gk.alert
+	if ams != nil {
+		alert.Alertmanager = ams
+		// alert state needs to be updated to only reflect selected instances
+		alert.State = instancesState(ams)
+	}
+	return alertGroupKey{keys: keys, alert: alert}
+}
+
+// instancesState returns the most important state from all given Alertmanager
+// instances
+func instancesState(ams []models.AlertmanagerInstance) string {
+	states := []string{}
+	for _, am := range ams {
+		states = append(states, am.State)
+	}
+	if slices.StringInSlice(states, models.AlertStateActive) {
+		return models.AlertStateActive
+	} else if slices.StringInSlice(states, models.AlertStateSuppressed) {
+		return models.AlertStateSuppressed
+	}
+	return models.AlertStateUnprocessed
+}
+
+// mergeAlerts merges two copies of the same alert routed to different
+// receivers, so it's only shown once in the regrouped alert group, receiver
+// with the lowest name is used and Alertmanager instances from both copies
+// are kept
+func mergeAlerts(alert, dup models.Alert) models.Alert {
+	if dup.Receiver < alert.Receiver {
+		alert.Receiver = dup.Receiver
+	}
+	if dup.StartsAt.Before(alert.StartsAt) {
+		alert.StartsAt = dup.StartsAt
+	}
+
+	ams := make([]models.AlertmanagerInstance, len(alert.Alertmanager), len(alert.Alertmanager)+len(dup.Alertmanager))
+	copy(ams, alert.Alertmanager)
+	for _, am := range dup.Alertmanager {
+		found := false
+		for _, existing := range ams {
+			if existing.Name == am.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ams = append(ams, am)
+		}
+	}
+	sort.Slice(ams, func(i, j int) bool {
+		return ams[i].Name < ams[j].Name
+	})
+	alert.Alertmanager = ams
+	alert.State = instancesState(ams)
+	return alert
+}
+
+func alertmanagerName(am models.AlertmanagerInstance) string {
+	return am.Name
+}
+
+func alertmanagerClusterName(am models.AlertmanagerInstance) string {
+	return am.ClusterName
+}
+
+// splitAlertByAlertmanager returns a copy of alert group key for every
+// distinct value of Alertmanager instance attribute, with only matching
+// instances left on each alert
+func splitAlertByAlertmanager(gk alertGroupKey, name string, attr func(models.AlertmanagerInstance) string) []alertGroupKey {
+	values := []string{}
+	ams := map[string][]models.AlertmanagerInstance{}
+	for _, am := range gk.alert.Alertmanager {
+		value := attr(am)
+		if _, found := ams[value]; !found {
+			values = append(values, value)
+		}
+		ams[value] = append(ams[value], am)
+	}
+
+	keys := []alertGroupKey{}
+	for _, value := range values {
+		keys = append(keys, gk.withKey(name, value, ams[value]))
+	}
+	return keys
+}
+
+// regroupAlerts returns alert groups with all alerts regrouped using values
+// of given labels, alerts can have multiple Alertmanager instances, so when
+// regrouping using @alertmanager or @cluster an alert might end up in multiple
+// groups, each only with instances matching that group
+func regroupAlerts(groups []models.AlertGroup, groupBy []string) []models.AlertGroup {
+	regrouped := map[string]*models.AlertGroup{}
+	// alert routed to multiple receivers is present in multiple groups, we
+	// need to track which alerts are already in each regrouped group and all
+	// receivers used for it
+	alertIndex := map[string]map[string]int{}
+	receivers := map[string][]string{}
+
+	for _, ag := range groups {
+		for _, alert := range ag.Alerts {
+			gks := []alertGroupKey{{keys: map[string]string{}, alert: alert}}
+			for _, name := range groupBy {
+				newGKs := []alertGroupKey{}
+				for _, gk := range gks {
+					switch name {
+					case "@alertmanager":
+						newGKs = append(newGKs, splitAlertByAlertmanager(gk, name, alertmanagerName)...)
+					case "@cluster":
+						newGKs = append(newGKs, splitAlertByAlertmanager(gk, name, alertmanagerClusterName)...)
+					case "@receiver":
+						newGKs = append(newGKs, gk.withKey(name, gk.alert.Receiver, nil))
+					default:
+						// alerts without this label are grouped together, same as
+						// Alertmanager does it
+						if value, found := gk.alert.Labels[name]; found {
+							newGKs = append(newGKs, gk.withKey(name, value, nil))
+						} else {
+							newGKs = append(newGKs, gk)
+						}
+					}
+				}
+				gks = newGKs
+			}
+
+			for _, gk := range gks {
+				// special labels are part of the group ID, but only real labels
+				// are set on the group, so they can be used to fill silence form
+				id := models.AlertGroup{Labels: gk.keys}.LabelsFingerprint()
+				g, found := regrouped[id]
+				if !found {
+					g = &models.AlertGroup{
+						ID:       id,
+						Receiver: gk.keys["@receiver"],
+						Labels:   map[string]string{},
+						Alerts:   models.AlertList{},
+					}
+					for k, v := range gk.keys {
+						if !strings.HasPrefix(k, "@") {
+							g.Labels[k] = v
+						}
+					}
+					regrouped[id] = g
+					alertIndex[id] = map[string]int{}
+				}
+				if !slices.StringInSlice(receivers[id], gk.alert.Receiver) {
+					receivers[id] = append(receivers[id], gk.alert.Receiver)
+				}
+				fp := gk.alert.LabelsFingerprint()
+				if i, found := alertIndex[id][fp]; found {
+					g.Alerts[i] = mergeAlerts(g.Alerts[i], gk.alert)
+				} else {
+					alertIndex[id][fp] = len(g.Alerts)
+					g.Alerts = append(g.Alerts, gk.alert)
+				}
+			}
+		}
+	}
+
+	newGroups := make([]models.AlertGroup, 0, len(regrouped))
+	for id, g := range regrouped {
+		// if alerts weren't regrouped by receiver but all of them are using
+		// the same one then set it on the group
+		if g.Receiver == "" && len(receivers[id]) == 1 {
+			g.Receiver = receivers[id][0]
+		}
+		// alerts were appended in the order of original groups, which is
+		// random, so sort them to keep the group hash stable
+		sort.Sort(g.Alerts)
+		g.LatestStartsAt = g.FindLatestStartsAt()
+		newGroups = append(newGroups, *g)
+	}
+	return newGroups
+}
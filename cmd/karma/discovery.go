@@ -0,0 +1,128 @@
+package main
+
+import (
+	"time"
+
+	"github.com/prymitive/karma/internal/config"
+	"github.com/prymitive/karma/internal/discovery"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// discovered holds the config of every upstream registered by each
+	// discovery config, keyed by the discovery name
+	discovered = map[string]map[string]config.AlertmanagerConfig{}
+	// discoveryLoops holds a channel for every running discovery loop, closing
+	// it will stop the loop
+	discoveryLoops = []chan struct{}{}
+)
+
+// discoverServers returns the list of Alertmanager servers found using given
+// discovery config
+func discoverServers(d config.DiscoveryConfig) ([]config.AlertmanagerConfig, error) {
+	targets := []string{}
+
+	if len(d.FileSD.Files) > 0 {
+		found, err := discovery.FileTargets(d.FileSD.Files)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, found...)
+	}
+
+	if len(d.DNSSD.Names) > 0 {
+		found, err := discovery.DNSTargets(d.DNSSD.Names, d.DNSSD.Type, d.DNSSD.Port, d.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, found...)
+	}
+
+	servers := []config.AlertmanagerConfig{}
+	for _, target := range targets {
+		s := d.AlertmanagerConfig
+		s.Name = discovery.TargetName(d.Name, target)
+		s.URI = discovery.TargetURI(target, d.Scheme, d.Path)
+		servers = append(servers, s)
+	}
+	return servers, nil
+}
+
+// runDiscovery will register all discovered Alertmanager servers and remove
+// all servers that are no longer found, if discovery fails we keep all
+// servers registered so far
+func runDiscovery(d config.DiscoveryConfig, stop chan struct{}) {
+	servers, err := discoverServers(d)
+	if err != nil {
+		log.Errorf("[%s] Alertmanager discovery failed, keeping current servers: %s", d.Name, err)
+		return
+	}
+
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
+	// configuration might have been reloaded while we were waiting for the lock
+	select {
+	case <-stop:
+		return
+	default:
+	}
+
+	log.Debugf("[%s] Discovered %d Alertmanager server(s)", d.Name, len(servers))
+	syncUpstreams(discovered[d.Name], servers)
+}
+
+func discoveryLoop(d config.DiscoveryConfig, stop chan struct{}) {
+	ticker := time.NewTicker(d.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		runDiscovery(d, stop)
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// restartDiscovery will stop all running discovery loops and start a new loop
+// for every discovery config passed here, servers registered by discovery
+// configs that are no longer present will be removed.
+// reloadLock must be held when calling it
+func restartDiscovery(discoveries []config.DiscoveryConfig) {
+	for _, stop := range discoveryLoops {
+		close(stop)
+	}
+	discoveryLoops = []chan struct{}{}
+
+	wanted := map[string]bool{}
+	for _, d := range discoveries {
+		wanted[d.Name] = true
+	}
+	for name, current := range discovered {
+		if !wanted[name] {
+			log.Infof("[%s] Alertmanager discovery removed", name)
+			syncUpstreams(current, []config.AlertmanagerConfig{})
+			delete(discovered, name)
+		}
+	}
+
+	for _, d := range discoveries {
+		if _, found := discovered[d.Name]; !found {
+			discovered[d.Name] = map[string]config.AlertmanagerConfig{}
+		}
+		log.Infof("[%s] Starting Alertmanager discovery with %s refresh interval", d.Name, d.RefreshInterval)
+		stop := make(chan struct{})
+		discoveryLoops = append(discoveryLoops, stop)
+		go discoveryLoop(d, stop)
+	}
+}
+
+// startDiscovery starts a background discovery loop for every discovery config
+func startDiscovery() {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
+	restartDiscovery(config.Config.Alertmanager.Discovery)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prymitive/karma/internal/alertmanager"
+	"github.com/prymitive/karma/internal/config"
+	"github.com/prymitive/karma/internal/mock"
+
+	"github.com/jarcoal/httpmock"
+	cache "github.com/patrickmn/go-cache"
+)
+
+func TestFileDiscovery(t *testing.T) {
+	mockConfig()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	apiCache = cache.New(cache.NoExpiration, 10*time.Second)
+
+	for _, path := range []string{"metrics", "api/v2/status", "api/v2/silences", "api/v2/alerts/groups"} {
+		mock.RegisterURL("http://discovered.localhost:9093/"+path, "0.19.0", path)
+	}
+
+	f, err := ioutil.TempFile("", "karma-file-sd-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.WriteString(`[{"targets": ["discovered.localhost:9093"]}]`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	d := config.DiscoveryConfig{Scheme: "http", RefreshInterval: time.Minute}
+	d.Name = "files"
+	d.Timeout = time.Second * 5
+	d.Interval = time.Minute
+	d.FileSD.Files = []string{f.Name()}
+
+	reloadLock.Lock()
+	discovered[d.Name] = map[string]config.AlertmanagerConfig{}
+	reloadLock.Unlock()
+	stop := make(chan struct{})
+
+	runDiscovery(d, stop)
+	am := alertmanager.GetAlertmanagerByName("files-discovered.localhost:9093")
+	if am == nil {
+		t.Fatal("Discovered Alertmanager wasn't registered")
+	}
+	if am.URI != "http://discovered.localhost:9093" {
+		t.Errorf("Discovered Alertmanager URI is %s", am.URI)
+	}
+	if !waitForAlerts(am) {
+		t.Error("Discovered Alertmanager has no alerts")
+	}
+
+	// nothing should change if discovery fails
+	d.FileSD.Files = []string{"["}
+	runDiscovery(d, stop)
+	if alertmanager.GetAlertmanagerByName("files-discovered.localhost:9093") != am {
+		t.Error("Discovered Alertmanager was modified after failed discovery")
+	}
+
+	if err = ioutil.WriteFile(f.Name(), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d.FileSD.Files = []string{f.Name()}
+	runDiscovery(d, stop)
+	if alertmanager.GetAlertmanagerByName("files-discovered.localhost:9093") != nil {
+		t.Error("Alertmanager that's no longer discovered is still registered")
+	}
+	if collectorRunning("files-discovered.localhost:9093") {
+		t.Error("Collection loop is still running for Alertmanager that's no longer discovered")
+	}
+
+	reloadLock.Lock()
+	delete(discovered, d.Name)
+	reloadLock.Unlock()
+}
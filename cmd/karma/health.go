@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/prymitive/karma/internal/alertmanager"
+	"github.com/prymitive/karma/internal/config"
+	"github.com/prymitive/karma/internal/models"
+)
+
+const (
+	upstreamPending = "pending"
+	upstreamHealthy = "healthy"
+	upstreamStale   = "stale"
+	upstreamFailed  = "failed"
+)
+
+var (
+	// initialPullDone is set to true once we finish the initial pull from
+	// all Alertmanager upstreams
+	initialPullDone = false
+	readyLock       = sync.RWMutex{}
+)
+
+func setReady(ready bool) {
+	readyLock.Lock()
+	defer readyLock.Unlock()
+
+	initialPullDone = ready
+}
+
+func isReady() bool {
+	readyLock.RLock()
+	defer readyLock.RUnlock()
+
+	return initialPullDone
+}
+
+// upstreamsHealth returns the state of every registered Alertmanager upstream
+// and the number of upstreams that are healthy
+func upstreamsHealth() ([]models.UpstreamHealth, int) {
+	upstreams := alertmanager.GetAlertmanagers()
+	statuses := make([]models.UpstreamHealth, 0, len(upstreams))
+	healthy := 0
+	for _, am := range upstreams {
+		status := models.UpstreamHealth{
+			Name:     am.Name,
+			LastPull: am.LastPull(),
+			Error:    am.Error(),
+		}
+		switch {
+		case status.Error == "" && status.LastPull.IsZero():
+			status.State = upstreamPending
+		case status.Error == "":
+			status.State = upstreamHealthy
+			healthy++
+		case am.IsStale():
+			status.State = upstreamStale
+		default:
+			status.State = upstreamFailed
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, healthy
+}
+
+// health handler reports karma liveness, it will always respond with 200
+// as long as karma is able to serve HTTP requests
+func health(c *gin.Context) {
+	noCache(c)
+
+	upstreams, _ := upstreamsHealth()
+	c.JSON(http.StatusOK, models.HealthResponse{
+		Status:    "ok",
+		Upstreams: upstreams,
+	})
+}
+
+// ready handler responds with 200 only after the initial pull from all
+// Alertmanager upstreams is done and there are enough healthy upstreams,
+// it responds with 503 otherwise
+func ready(c *gin.Context) {
+	noCache(c)
+
+	upstreams, healthy := upstreamsHealth()
+	resp := models.HealthResponse{
+		Status:    "ok",
+		Upstreams: upstreams,
+	}
+	code := http.StatusOK
+
+	minHealthy := config.Config.Health.MinHealthyUpstreams
+	switch {
+	case !isReady():
+		code = http.StatusServiceUnavailable
+		resp.Status = "error"
+		resp.Message = "initial Alertmanager pull is still running"
+	case healthy < minHealthy:
+		code = http.StatusServiceUnavailable
+		resp.Status = "error"
+		resp.Message = fmt.Sprintf("%d healthy Alertmanager upstream(s), at least %d required", healthy, minHealthy)
+	}
+
+	c.JSON(code, resp)
+}
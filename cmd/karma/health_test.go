@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prymitive/karma/internal/config"
+	"github.com/prymitive/karma/internal/mock"
+	"github.com/prymitive/karma/internal/models"
+)
+
+func TestHealth(t *testing.T) {
+	mockConfig()
+	r := ginTestEngine()
+	req := httptest.NewRequest("GET", "/health", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("GET /health returned status %d", resp.Code)
+	}
+
+	ur := models.HealthResponse{}
+	if err := json.Unmarshal(resp.Body.Bytes(), &ur); err != nil {
+		t.Fatalf("Failed to unmarshal response: %s", err)
+	}
+	if ur.Status != "ok" {
+		t.Errorf("Got status '%s', expected 'ok'", ur.Status)
+	}
+	if len(ur.Upstreams) != 1 || ur.Upstreams[0].Name != "default" {
+		t.Errorf("Expected a single 'default' upstream, got %v", ur.Upstreams)
+	}
+}
+
+type readyTest struct {
+	ready      bool
+	minHealthy int
+	code       int
+	status     string
+}
+
+var readyTests = []readyTest{
+	{ready: false, minHealthy: 0, code: http.StatusServiceUnavailable, status: "error"},
+	{ready: true, minHealthy: 0, code: http.StatusOK, status: "ok"},
+	{ready: true, minHealthy: 1, code: http.StatusOK, status: "ok"},
+	{ready: true, minHealthy: 2, code: http.StatusServiceUnavailable, status: "error"},
+}
+
+func TestReady(t *testing.T) {
+	mockConfig()
+	defer setReady(false)
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+		for _, testCase := range readyTests {
+			setReady(testCase.ready)
+			config.Config.Health.MinHealthyUpstreams = testCase.minHealthy
+
+			req := httptest.NewRequest("GET", "/ready", nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			if resp.Code != testCase.code {
+				t.Errorf("[%s] GET /ready with %+v returned status %d", version, testCase, resp.Code)
+			}
+
+			ur := models.HealthResponse{}
+			if err := json.Unmarshal(resp.Body.Bytes(), &ur); err != nil {
+				t.Fatalf("Failed to unmarshal response: %s", err)
+			}
+			if ur.Status != testCase.status {
+				t.Errorf("[%s] GET /ready with %+v returned '%s' status, expected '%s'", version, testCase, ur.Status, testCase.status)
+			}
+			if len(ur.Upstreams) != 1 || ur.Upstreams[0].State != upstreamHealthy {
+				t.Errorf("[%s] Expected a single healthy upstream, got %v", version, ur.Upstreams)
+			}
+		}
+	}
+	config.Config.Health.MinHealthyUpstreams = 0
+}
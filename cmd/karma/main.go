@@ -16,6 +16,7 @@ import (
 	"github.com/prymitive/karma/internal/config"
 	"github.com/prymitive/karma/internal/models"
 	"github.com/prymitive/karma/internal/transform"
+	"github.com/prymitive/karma/internal/uri"
 
 	"github.com/DeanThompson/ginpprof"
 	"github.com/gin-contrib/cors"
@@ -34,10 +35,6 @@ import (
 var (
 	version = "dev"
 
-	// ticker is a timer used by background loop that will keep pulling
-	// data from Alertmanager
-	ticker *time.Ticker
-
 	// apiCache will be used to keep short lived copy of JSON reponses generated for the UI
 	// If there are requests with the same filter we should respond from cache
 	// rather than do all the filtering every time
@@ -65,8 +62,20 @@ func customJS(c *gin.Context) {
 	serveFileOr404(config.Config.Custom.JS, "application/javascript", c)
 }
 
+// skipGzipForStreams wraps gzip middleware so it's not used for streaming
+// endpoints, gzip writer buffers all data until the response is complete
+func skipGzipForStreams(gz gin.HandlerFunc) gin.HandlerFunc {
+	streamPath := getViewURL("/alerts/stream")
+	return func(c *gin.Context) {
+		if c.Request.URL.Path == streamPath {
+			return
+		}
+		gz(c)
+	}
+}
+
 func setupRouter(router *gin.Engine) {
-	router.Use(gzip.Gzip(gzip.DefaultCompression))
+	router.Use(skipGzipForStreams(gzip.Gzip(gzip.DefaultCompression)))
 
 	router.Use(setStaticHeaders(getViewURL("/static/")))
 	router.Use(static.Serve(getViewURL("/"), staticBuildFileSystem))
@@ -97,14 +106,21 @@ func setupRouter(router *gin.Engine) {
 	}))
 
 	router.GET(getViewURL("/"), index)
+	router.GET(getViewURL("/health"), health)
+	router.GET(getViewURL("/ready"), ready)
 	router.GET(getViewURL("/alerts.json"), alerts)
+	router.GET(getViewURL("/alerts/stream"), alertsStream)
 	router.GET(getViewURL("/autocomplete.json"), autocomplete)
+	router.GET(getViewURL("/validate.json"), validate)
+	router.GET(getViewURL("/views.json"), namedViews)
 	router.GET(getViewURL("/labelNames.json"), knownLabelNames)
 	router.GET(getViewURL("/labelValues.json"), knownLabelValues)
 
 	router.GET(getViewURL("/custom.css"), customCSS)
 	router.GET(getViewURL("/custom.js"), customJS)
 
+	setupRouterProxyHandlers(router)
+
 	router.NoRoute(notFound)
 }
 
@@ -113,38 +129,71 @@ func setupMetrics(router *gin.Engine) {
 	router.GET(getViewURL("/metrics"), promHandler(promhttp.Handler()))
 }
 
-func setupUpstreams() {
-	for _, s := range config.Config.Alertmanager.Servers {
-
-		var httpTransport http.RoundTripper
-		var err error
-		// if either TLS root CA or client cert is configured then initialize custom transport where we have this setup
-		if s.TLS.CA != "" || s.TLS.Cert != "" || s.TLS.InsecureSkipVerify {
-			httpTransport, err = alertmanager.NewHTTPTransport(s.TLS.CA, s.TLS.Cert, s.TLS.Key, s.TLS.InsecureSkipVerify)
-			if err != nil {
-				log.Fatalf("Failed to create HTTP transport for Alertmanager '%s' with URI '%s': %s", s.Name, s.URI, err)
-			}
+func newUpstream(s config.AlertmanagerConfig) (*alertmanager.Alertmanager, error) {
+	var httpTransport http.RoundTripper
+	var err error
+	// if either TLS root CA or client cert is configured then initialize custom transport where we have this setup
+	if s.TLS.CA != "" || s.TLS.Cert != "" || s.TLS.InsecureSkipVerify {
+		httpTransport, err = alertmanager.NewHTTPTransport(s.TLS.CA, s.TLS.Cert, s.TLS.Key, s.TLS.InsecureSkipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP transport for Alertmanager '%s' with URI '%s': %s", s.Name, uri.SanitizeURI(s.URI), err)
 		}
+	}
 
-		am, err := alertmanager.NewAlertmanager(
-			s.Name,
-			s.URI,
-			alertmanager.WithExternalURI(s.ExternalURI),
-			alertmanager.WithRequestTimeout(s.Timeout),
-			alertmanager.WithProxy(s.Proxy),
-			alertmanager.WithHTTPTransport(httpTransport), // we will pass a nil unless TLS.CA or TLS.Cert is set
-			alertmanager.WithHTTPHeaders(s.Headers),
-		)
+	// servers created without global defaults might not set those
+	var retryAttempts, breakerFailures int
+	if s.Retry.Attempts != nil {
+		retryAttempts = *s.Retry.Attempts
+	}
+	if s.CircuitBreaker.Failures != nil {
+		breakerFailures = *s.CircuitBreaker.Failures
+	}
+
+	am, err := alertmanager.NewAlertmanager(
+		s.Name,
+		s.URI,
+		alertmanager.WithExternalURI(s.ExternalURI),
+		alertmanager.WithCluster(s.Cluster),
+		alertmanager.WithRequestTimeout(s.Timeout),
+		alertmanager.WithInterval(s.Interval),
+		alertmanager.WithGracePeriod(s.GracePeriod),
+		alertmanager.WithRetry(retryAttempts, s.Retry.Backoff),
+		alertmanager.WithCircuitBreaker(breakerFailures, s.CircuitBreaker.Cooldown),
+		alertmanager.WithProxy(s.Proxy),
+		alertmanager.WithVersion(s.Version),
+		alertmanager.WithHTTPTransport(httpTransport), // we will pass a nil unless TLS.CA or TLS.Cert is set
+		alertmanager.WithHTTPHeaders(s.Headers),
+		alertmanager.WithReceivers(config.Config.Receivers.Keep),
+		alertmanager.WithMatchers(s.Matchers),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Alertmanager '%s' with URI '%s': %s", s.Name, uri.SanitizeURI(s.URI), err)
+	}
+	return am, nil
+}
+
+func setupUpstreams() {
+	for _, s := range config.Config.Alertmanager.Servers {
+		am, err := newUpstream(s)
 		if err != nil {
-			log.Fatalf("Failed to create Alertmanager '%s' with URI '%s': %s", s.Name, s.URI, err)
+			log.Fatal(err)
 		}
 		err = alertmanager.RegisterAlertmanager(am)
 		if err != nil {
 			log.Fatalf("Failed to register Alertmanager '%s' with URI '%s': %s", s.Name, s.URI, err)
 		}
+		upstreamConfigs[s.Name] = s
 	}
 }
 
+func setupJIRARules() {
+	jiraRules := []models.JiraRule{}
+	for _, rule := range config.Config.JIRA {
+		jiraRules = append(jiraRules, models.JiraRule{Regex: rule.Regex, URI: rule.URI})
+	}
+	transform.ParseRules(jiraRules)
+}
+
 func setupLogger() {
 	switch config.Config.Log.Level {
 	case "debug":
@@ -176,6 +225,8 @@ func setupLogger() {
 func main() {
 	printVersion := pflag.Bool("version", false, "Print version and exit")
 	validateConfig := pflag.Bool("check-config", false, "Validate configuration and exit")
+	watchConfig := pflag.Duration("config.watch", 0,
+		"Check the configuration file for changes at this interval and reload it when modified, 0 disables it")
 	pflag.Parse()
 
 	if *printVersion {
@@ -196,17 +247,13 @@ func main() {
 		config.Config.LogValues()
 	}
 
-	jiraRules := []models.JiraRule{}
-	for _, rule := range config.Config.JIRA {
-		jiraRules = append(jiraRules, models.JiraRule{Regex: rule.Regex, URI: rule.URI})
-	}
-	transform.ParseRules(jiraRules)
+	setupJIRARules()
 
 	apiCache = cache.New(cache.NoExpiration, 10*time.Second)
 
 	setupUpstreams()
 
-	if len(alertmanager.GetAlertmanagers()) == 0 {
+	if len(alertmanager.GetAlertmanagers()) == 0 && len(config.Config.Alertmanager.Discovery) == 0 {
 		log.Fatal("No valid Alertmanager URIs defined")
 	}
 
@@ -215,15 +262,6 @@ func main() {
 		return
 	}
 
-	// before we start try to fetch data from Alertmanager
-	log.Info("Initial Alertmanager query")
-	pullFromAlertmanager()
-	log.Info("Done, starting HTTP server")
-
-	// background loop that will fetch updates from Alertmanager
-	ticker = time.NewTicker(config.Config.Alertmanager.Interval)
-	go Tick()
-
 	switch config.Config.Debug {
 	case true:
 		gin.SetMode(gin.DebugMode)
@@ -249,18 +287,15 @@ func main() {
 	}
 
 	setupRouter(router)
-	for _, am := range alertmanager.GetAlertmanagers() {
-		err := setupRouterProxyHandlers(router, am)
-		if err != nil {
-			log.Fatalf("Failed to setup proxy handlers for Alertmanager '%s': %s", am.Name, err)
-		}
-	}
 
 	listen := fmt.Sprintf("%s:%d", config.Config.Listen.Address, config.Config.Listen.Port)
 	httpServer := &http.Server{
 		Addr:    listen,
 		Handler: router,
 	}
+	// streaming clients would keep connections open forever, so they need
+	// to be told to disconnect when we shut down
+	httpServer.RegisterOnShutdown(pullNotify.close)
 
 	go func() {
 		if err := httpServer.ListenAndServe(); err != nil {
@@ -268,6 +303,24 @@ func main() {
 		}
 	}()
 
+	// fetch data from Alertmanager before we report as ready, HTTP server is
+	// already running so health checks will work while we wait
+	log.Info("Initial Alertmanager query")
+	pullFromAlertmanager()
+	setReady(true)
+	log.Info("Done, karma is ready")
+
+	// background loops that will fetch updates from each Alertmanager
+	Tick()
+
+	// background loops that will register discovered Alertmanager servers
+	startDiscovery()
+
+	go reloadOnSignal()
+	if *watchConfig > 0 && config.File() != "" {
+		go reloadOnFileChange(config.File(), *watchConfig)
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
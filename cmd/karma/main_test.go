@@ -46,6 +46,9 @@ func TestMetrics(t *testing.T) {
 		"karma_collected_alerts_count",
 		"karma_collect_cycles_total",
 		"karma_alertmanager_errors_total",
+		"karma_alertmanager_retries_total",
+		"karma_alertmanager_circuit_breaker_trips_total",
+		"karma_alertmanager_circuit_breaker_state",
 	} {
 		if !strings.Contains(body, s) {
 			t.Errorf("Metric '%s' missing from /metrics response", s)
@@ -10,6 +10,9 @@ type karmaCollector struct {
 	collectedGroups *prometheus.Desc
 	cyclesTotal     *prometheus.Desc
 	errorsTotal     *prometheus.Desc
+	retriesTotal    *prometheus.Desc
+	breakerTrips    *prometheus.Desc
+	breakerState    *prometheus.Desc
 }
 
 func newKarmaCollector() *karmaCollector {
@@ -38,6 +41,24 @@ func newKarmaCollector() *karmaCollector {
 			[]string{"alertmanager", "endpoint"},
 			prometheus.Labels{},
 		),
+		retriesTotal: prometheus.NewDesc(
+			"karma_alertmanager_retries_total",
+			"Total number of retries after a failed request to Alertmanager API",
+			[]string{"alertmanager"},
+			prometheus.Labels{},
+		),
+		breakerTrips: prometheus.NewDesc(
+			"karma_alertmanager_circuit_breaker_trips_total",
+			"Total number of times circuit breaker opened after consecutive Alertmanager API failures",
+			[]string{"alertmanager"},
+			prometheus.Labels{},
+		),
+		breakerState: prometheus.NewDesc(
+			"karma_alertmanager_circuit_breaker_state",
+			"Current state of the Alertmanager circuit breaker, 1 for the active state",
+			[]string{"alertmanager", "state"},
+			prometheus.Labels{},
+		),
 	}
 }
 
@@ -46,6 +67,9 @@ func (c *karmaCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.collectedGroups
 	ch <- c.cyclesTotal
 	ch <- c.errorsTotal
+	ch <- c.retriesTotal
+	ch <- c.breakerTrips
+	ch <- c.breakerState
 }
 
 func (c *karmaCollector) Collect(ch chan<- prometheus.Metric) {
@@ -68,6 +92,32 @@ func (c *karmaCollector) Collect(ch chan<- prometheus.Metric) {
 				key,
 			)
 		}
+		ch <- prometheus.MustNewConstMetric(
+			c.retriesTotal,
+			prometheus.CounterValue,
+			am.Metrics.Retries,
+			am.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.breakerTrips,
+			prometheus.CounterValue,
+			am.Metrics.BreakerTrips,
+			am.Name,
+		)
+		breakerState := am.BreakerState()
+		for _, state := range alertmanager.BreakerStates {
+			var val float64
+			if state == breakerState {
+				val = 1
+			}
+			ch <- prometheus.MustNewConstMetric(
+				c.breakerState,
+				prometheus.GaugeValue,
+				val,
+				am.Name,
+				state,
+			)
+		}
 
 		// receiver name -> count
 		groupsByReceiver := map[string]float64{}
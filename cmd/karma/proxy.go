@@ -6,6 +6,7 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prymitive/karma/internal/alertmanager"
@@ -14,6 +15,18 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+var (
+	// proxies holds a proxy for every Alertmanager upstream that received any
+	// proxied request, proxies are keyed by upstream name
+	proxies     = map[string]alertmanagerProxy{}
+	proxiesLock = sync.Mutex{}
+)
+
+type alertmanagerProxy struct {
+	upstream *alertmanager.Alertmanager
+	proxy    *httputil.ReverseProxy
+}
+
 func proxyPathPrefix(name string) string {
 	maybeSlash := ""
 	if !strings.HasSuffix(config.Config.Listen.Prefix, "/") {
@@ -69,22 +82,57 @@ func NewAlertmanagerProxy(alertmanager *alertmanager.Alertmanager) (*httputil.Re
 	return &proxy, nil
 }
 
-func setupRouterProxyHandlers(router *gin.Engine, alertmanager *alertmanager.Alertmanager) error {
-	proxy, err := NewAlertmanagerProxy(alertmanager)
+// getAlertmanagerProxy returns the proxy for given alertmanager instance, proxy
+// is created on first use and re-created if the instance was replaced
+func getAlertmanagerProxy(upstream *alertmanager.Alertmanager) (*httputil.ReverseProxy, error) {
+	proxiesLock.Lock()
+	defer proxiesLock.Unlock()
+
+	if p, found := proxies[upstream.Name]; found && p.upstream == upstream {
+		return p.proxy, nil
+	}
+
+	proxy, err := NewAlertmanagerProxy(upstream)
+	if err != nil {
+		return nil, err
+	}
+	proxies[upstream.Name] = alertmanagerProxy{upstream: upstream, proxy: proxy}
+	return proxy, nil
+}
+
+// removeAlertmanagerProxy drops the proxy for alertmanager instance with
+// given name, it's called when the instance is removed
+func removeAlertmanagerProxy(name string) {
+	proxiesLock.Lock()
+	defer proxiesLock.Unlock()
+
+	delete(proxies, name)
+}
+
+// proxyHandler will proxy requests to the Alertmanager instance matching the
+// name from the request path, instances are looked up on every request so
+// proxy will follow any changes made when configuration is reloaded
+func proxyHandler(c *gin.Context) {
+	name := c.Param("name")
+	upstream := alertmanager.GetAlertmanagerByName(name)
+	if upstream == nil {
+		notFound(c)
+		return
+	}
+
+	proxy, err := getAlertmanagerProxy(upstream)
 	if err != nil {
-		return err
+		log.Errorf("[%s] Failed to create proxy: %s", upstream.Name, err)
+		c.String(http.StatusInternalServerError, err.Error())
+		return
 	}
-	router.POST(
-		proxyPath(alertmanager.Name, "/api/v1/silences"),
-		gin.WrapH(http.StripPrefix(proxyPathPrefix(alertmanager.Name), proxy)))
-	router.DELETE(
-		proxyPath(alertmanager.Name, "/api/v1/silence/*id"),
-		gin.WrapH(http.StripPrefix(proxyPathPrefix(alertmanager.Name), proxy)))
-	router.POST(
-		proxyPath(alertmanager.Name, "/api/v2/silences"),
-		gin.WrapH(http.StripPrefix(proxyPathPrefix(alertmanager.Name), proxy)))
-	router.DELETE(
-		proxyPath(alertmanager.Name, "/api/v2/silence/*id"),
-		gin.WrapH(http.StripPrefix(proxyPathPrefix(alertmanager.Name), proxy)))
-	return nil
+
+	http.StripPrefix(proxyPathPrefix(name), proxy).ServeHTTP(c.Writer, c.Request)
+}
+
+func setupRouterProxyHandlers(router *gin.Engine) {
+	router.POST(proxyPath(":name", "/api/v1/silences"), proxyHandler)
+	router.DELETE(proxyPath(":name", "/api/v1/silence/*id"), proxyHandler)
+	router.POST(proxyPath(":name", "/api/v2/silences"), proxyHandler)
+	router.DELETE(proxyPath(":name", "/api/v2/silence/*id"), proxyHandler)
 }
@@ -95,10 +95,16 @@ func TestProxy(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	err = setupRouterProxyHandlers(r, am)
+	err = alertmanager.RegisterAlertmanager(am)
 	if err != nil {
-		t.Errorf("Failed to setup proxy for Alertmanager %s: %s", am.Name, err)
+		t.Errorf("Failed to register Alertmanager %s: %s", am.Name, err)
 	}
+	defer func() {
+		err = alertmanager.UnregisterAlertmanager(am.Name)
+		if err != nil {
+			t.Error(err)
+		}
+	}()
 
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -189,9 +195,9 @@ func TestProxyHeaders(t *testing.T) {
 		if err != nil {
 			t.Error(err)
 		}
-		err = setupRouterProxyHandlers(r, am)
+		err = alertmanager.RegisterAlertmanager(am)
 		if err != nil {
-			t.Errorf("Failed to setup proxy for Alertmanager %s: %s", am.Name, err)
+			t.Errorf("Failed to register Alertmanager %s: %s", am.Name, err)
 		}
 
 		httpmock.Reset()
@@ -223,6 +229,11 @@ func TestProxyHeaders(t *testing.T) {
 			t.Errorf("%s %s proxied to %s returned status %d while %d was expected",
 				testCase.method, testCase.localPath, testCase.upstreamURI, resp.Code, testCase.code)
 		}
+
+		err = alertmanager.UnregisterAlertmanager(am.Name)
+		if err != nil {
+			t.Error(err)
+		}
 	}
 }
 
@@ -240,10 +251,16 @@ func TestProxyToSubURIAlertmanager(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	err = setupRouterProxyHandlers(r, am)
+	err = alertmanager.RegisterAlertmanager(am)
 	if err != nil {
-		t.Errorf("Failed to setup proxy for Alertmanager %s: %s", am.Name, err)
+		t.Errorf("Failed to register Alertmanager %s: %s", am.Name, err)
 	}
+	defer func() {
+		err = alertmanager.UnregisterAlertmanager(am.Name)
+		if err != nil {
+			t.Error(err)
+		}
+	}()
 
 	httpmock.RegisterResponder("POST", "http://alertmanager.example.com/suburi/api/v1/silences", func(req *http.Request) (*http.Response, error) {
 		return httpmock.NewStringResponse(200, "ok"), nil
@@ -256,3 +273,37 @@ func TestProxyToSubURIAlertmanager(t *testing.T) {
 		t.Errorf("Got response code %d instead of 200", resp.Code)
 	}
 }
+
+func TestAlertmanagerProxyReuse(t *testing.T) {
+	am, err := alertmanager.NewAlertmanager("reuse", "http://localhost:9093", alertmanager.WithProxy(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAlertmanagerProxy(am.Name)
+
+	proxy, err := getAlertmanagerProxy(am)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := getAlertmanagerProxy(am); p != proxy {
+		t.Error("Proxy wasn't reused for the same Alertmanager instance")
+	}
+
+	// instance with the same name was created on reload
+	replaced, err := alertmanager.NewAlertmanager("reuse", "http://localhost:9094", alertmanager.WithProxy(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := getAlertmanagerProxy(replaced)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == proxy {
+		t.Error("Proxy wasn't re-created for replaced Alertmanager instance")
+	}
+
+	removeAlertmanagerProxy(am.Name)
+	if recreated, _ := getAlertmanagerProxy(replaced); recreated == p {
+		t.Error("Proxy wasn't re-created after it was removed")
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prymitive/karma/internal/alertmanager"
+	"github.com/prymitive/karma/internal/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// upstreamConfigs holds the config used to create every registered
+	// Alertmanager upstream, it's used to tell which upstreams were modified
+	// when configuration is reloaded
+	upstreamConfigs = map[string]config.AlertmanagerConfig{}
+	// reloadLock ensures that only a single reload or discovery update is
+	// running at any time
+	reloadLock = sync.Mutex{}
+)
+
+// reloadUpstreams will add, remove or replace Alertmanager upstreams so they
+// match the list of servers passed here
+func reloadUpstreams(servers []config.AlertmanagerConfig) {
+	syncUpstreams(upstreamConfigs, servers)
+}
+
+// syncUpstreams will add, remove or replace Alertmanager upstreams tracked
+// in the current map so they match the list of servers passed here, current
+// map is updated to reflect all changes
+func syncUpstreams(current map[string]config.AlertmanagerConfig, servers []config.AlertmanagerConfig) {
+	wanted := map[string]config.AlertmanagerConfig{}
+	for _, s := range servers {
+		wanted[s.Name] = s
+	}
+
+	// first create instances for every new or modified upstream, if that
+	// fails we keep the current instance
+	added := []*alertmanager.Alertmanager{}
+	replaced := map[string]bool{}
+	for _, s := range servers {
+		if cfg, found := current[s.Name]; found && reflect.DeepEqual(cfg, s) {
+			continue
+		}
+		am, err := newUpstream(s)
+		if err != nil {
+			log.Errorf("Failed to setup Alertmanager '%s': %s", s.Name, err)
+			continue
+		}
+		added = append(added, am)
+		if _, found := current[s.Name]; found {
+			replaced[s.Name] = true
+			// keep serving data from the current instance until the new one
+			// is done with the first pull, so alerts don't disappear
+			if old := alertmanager.GetAlertmanagerByName(s.Name); old != nil {
+				am.CopyData(old)
+			}
+		}
+	}
+
+	for name := range current {
+		if _, found := wanted[name]; found && !replaced[name] {
+			continue
+		}
+		stopCollector(name)
+		if err := alertmanager.UnregisterAlertmanager(name); err != nil {
+			log.Errorf("Failed to remove Alertmanager '%s': %s", name, err)
+		}
+		removeAlertmanagerProxy(name)
+		delete(current, name)
+	}
+
+	for _, am := range added {
+		if err := alertmanager.RegisterAlertmanager(am); err != nil {
+			log.Errorf("Failed to register Alertmanager '%s': %s", am.Name, err)
+			continue
+		}
+		current[am.Name] = wanted[am.Name]
+		// new instances have no fresh data yet, so the collection loop will
+		// pull from those right away, this is done in the background so we
+		// don't block while holding reloadLock
+		startCollector(am, true)
+	}
+}
+
+// reloadConfig will read configuration again and apply all changes that
+// don't require a restart, if the new configuration is invalid it's ignored
+func reloadConfig() {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
+	log.Info("Reloading configuration")
+	err := config.Config.Reload()
+	if err != nil {
+		log.Errorf("Failed to reload configuration, keeping the current one: %s", err)
+		return
+	}
+
+	setupJIRARules()
+	reloadUpstreams(config.Config.Alertmanager.Servers)
+	restartDiscovery(config.Config.Alertmanager.Discovery)
+
+	// cached responses might include data from removed upstreams or colors
+	// generated using old rules
+	apiCache.Flush()
+	log.Info("Configuration reloaded")
+}
+
+// reloadOnSignal will reload configuration every time karma receives SIGHUP
+func reloadOnSignal() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		log.Info("Got SIGHUP")
+		reloadConfig()
+	}
+}
+
+func configFileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Errorf("Failed to check config file %s: %s", path, err)
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// reloadOnFileChange will check the config file for changes every interval
+// and reload configuration if it was modified
+func reloadOnFileChange(path string, interval time.Duration) {
+	log.Infof("Watching config file %s for changes every %s", path, interval)
+	lastModified := configFileModTime(path)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		modified := configFileModTime(path)
+		if modified.IsZero() || modified.Equal(lastModified) {
+			continue
+		}
+		lastModified = modified
+		log.Infof("Config file %s was modified", path)
+		reloadConfig()
+	}
+}
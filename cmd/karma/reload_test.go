@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prymitive/karma/internal/alertmanager"
+	"github.com/prymitive/karma/internal/config"
+	"github.com/prymitive/karma/internal/mock"
+	"github.com/prymitive/karma/internal/models"
+
+	"github.com/jarcoal/httpmock"
+	cache "github.com/patrickmn/go-cache"
+)
+
+func collectorRunning(name string) bool {
+	collectorsLock.Lock()
+	defer collectorsLock.Unlock()
+
+	_, found := collectors[name]
+	return found
+}
+
+// waitForAlerts waits until the first pull from given Alertmanager is done,
+// it returns false if there are still no alerts after a few seconds
+func waitForAlerts(am *alertmanager.Alertmanager) bool {
+	for i := 0; i < 100; i++ {
+		if len(am.Alerts()) > 0 {
+			return true
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+	return false
+}
+
+func TestReloadUpstreams(t *testing.T) {
+	mockConfig()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	apiCache = cache.New(cache.NoExpiration, 10*time.Second)
+
+	uri := "http://reload.localhost"
+	for _, path := range []string{"metrics", "api/v2/status", "api/v2/silences", "api/v2/alerts/groups"} {
+		mock.RegisterURL(uri+"/"+path, "0.19.0", path)
+	}
+
+	original := make([]config.AlertmanagerConfig, len(config.Config.Alertmanager.Servers))
+	copy(original, config.Config.Alertmanager.Servers)
+	defer reloadUpstreams(original)
+	defaultAM := alertmanager.GetAlertmanagerByName("default")
+
+	added := config.AlertmanagerConfig{
+		Name:     "reload",
+		URI:      uri,
+		Timeout:  time.Second * 5,
+		Interval: time.Minute,
+	}
+	reloadUpstreams(append(original[:len(original):len(original)], added))
+	am := alertmanager.GetAlertmanagerByName("reload")
+	if am == nil {
+		t.Fatal("New Alertmanager wasn't registered after reload")
+	}
+	if !waitForAlerts(am) {
+		t.Error("New Alertmanager has no alerts after reload")
+	}
+	if !collectorRunning("reload") {
+		t.Error("Collection loop wasn't started for new Alertmanager")
+	}
+	if alertmanager.GetAlertmanagerByName("default") != defaultAM {
+		t.Error("Unmodified Alertmanager was replaced after reload")
+	}
+
+	modified := added
+	modified.Interval = time.Hour
+	reloadUpstreams(append(original[:len(original):len(original)], modified))
+	replaced := alertmanager.GetAlertmanagerByName("reload")
+	if replaced == nil || replaced == am {
+		t.Fatal("Modified Alertmanager wasn't replaced after reload")
+	}
+	if replaced.Interval != time.Hour {
+		t.Errorf("Modified Alertmanager interval is %s, expected %s", replaced.Interval, time.Hour)
+	}
+	if !waitForAlerts(replaced) {
+		t.Error("Modified Alertmanager has no alerts after reload")
+	}
+
+	reloadUpstreams(original)
+	if alertmanager.GetAlertmanagerByName("reload") != nil {
+		t.Error("Removed Alertmanager is still registered after reload")
+	}
+	if collectorRunning("reload") {
+		t.Error("Collection loop is still running for removed Alertmanager")
+	}
+	if alertmanager.GetAlertmanagerByName("default") != defaultAM {
+		t.Error("Unmodified Alertmanager was replaced after reload")
+	}
+}
+
+func TestReloadUpstreamsDoesntWaitForPull(t *testing.T) {
+	mockConfig()
+
+	// upstream that won't respond until we let it
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	original := make([]config.AlertmanagerConfig, len(config.Config.Alertmanager.Servers))
+	copy(original, config.Config.Alertmanager.Servers)
+
+	done := make(chan struct{})
+	go func() {
+		reloadUpstreams(append(original[:len(original):len(original)], config.AlertmanagerConfig{
+			Name:     "reload-slow",
+			URI:      server.URL,
+			Timeout:  time.Second * 5,
+			Interval: time.Minute,
+		}))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("reloadUpstreams() is blocked by a pull from new Alertmanager")
+	}
+	am := alertmanager.GetAlertmanagerByName("reload-slow")
+	if am == nil {
+		t.Fatal("New Alertmanager wasn't registered after reload")
+	}
+	if !collectorRunning("reload-slow") {
+		t.Error("Collection loop wasn't started for new Alertmanager")
+	}
+
+	// let the pull finish before we remove this instance
+	close(unblock)
+	for i := 0; i < 100 && am.Error() == ""; i++ {
+		time.Sleep(time.Millisecond * 50)
+	}
+	if am.Error() == "" {
+		t.Error("Pull from new Alertmanager didn't fail")
+	}
+	reloadUpstreams(original)
+}
+
+func TestReloadUpstreamsKeepsModifiedAlerts(t *testing.T) {
+	mockConfig()
+	apiCache = cache.New(cache.NoExpiration, 10*time.Second)
+
+	// upstream that will serve mock files until it's blocked, after that it
+	// won't respond until we let it
+	blocked := make(chan struct{})
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-blocked:
+			<-unblock
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		default:
+		}
+		body, err := ioutil.ReadFile(mock.GetAbsoluteMockPath(strings.TrimPrefix(r.URL.Path, "/"), "0.19.0"))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	original := make([]config.AlertmanagerConfig, len(config.Config.Alertmanager.Servers))
+	copy(original, config.Config.Alertmanager.Servers)
+
+	added := config.AlertmanagerConfig{
+		Name:     "reload-modified",
+		URI:      server.URL,
+		Timeout:  time.Second * 5,
+		Interval: time.Minute,
+	}
+	reloadUpstreams(append(original[:len(original):len(original)], added))
+	am := alertmanager.GetAlertmanagerByName("reload-modified")
+	if am == nil {
+		t.Fatal("New Alertmanager wasn't registered after reload")
+	}
+	if !waitForAlerts(am) {
+		t.Fatal("New Alertmanager has no alerts after reload")
+	}
+
+	// pull from the modified instance won't finish until we unblock it
+	close(blocked)
+	modified := added
+	modified.Interval = time.Hour
+	reloadUpstreams(append(original[:len(original):len(original)], modified))
+	replaced := alertmanager.GetAlertmanagerByName("reload-modified")
+	if replaced == nil || replaced == am {
+		t.Fatal("Modified Alertmanager wasn't replaced after reload")
+	}
+
+	apiCache.Flush()
+	r := ginTestEngine()
+	req := httptest.NewRequest("GET", "/alerts.json?q=@alertmanager=reload-modified", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	ur := models.AlertsResponse{}
+	if err := json.Unmarshal(resp.Body.Bytes(), &ur); err != nil {
+		t.Errorf("Failed to unmarshal response: %s", err)
+	}
+	if ur.TotalAlerts == 0 {
+		t.Error("Alerts from modified Alertmanager are missing right after reload")
+	}
+
+	// let the pull finish before we remove this instance
+	close(unblock)
+	for i := 0; i < 100 && replaced.Error() == ""; i++ {
+		time.Sleep(time.Millisecond * 50)
+	}
+	reloadUpstreams(original)
+	apiCache.Flush()
+}
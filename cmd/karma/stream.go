@@ -0,0 +1,131 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// how often we send keep alive events to streaming clients, so proxies won't
+// close idle connections
+var streamKeepAliveInterval = time.Second * 30
+
+// pullNotifier tells every streaming client that data was pulled from some
+// Alertmanager upstream and it should check if there's anything new to send
+type pullNotifier struct {
+	lock        sync.Mutex
+	subscribers map[chan struct{}]bool
+	closed      chan struct{}
+}
+
+func newPullNotifier() *pullNotifier {
+	return &pullNotifier{
+		subscribers: map[chan struct{}]bool{},
+		closed:      make(chan struct{}),
+	}
+}
+
+// subscribe returns a channel that will receive a message after every pull,
+// multiple pulls that happened while client was busy are merged into one
+func (n *pullNotifier) subscribe() chan struct{} {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	ch := make(chan struct{}, 1)
+	n.subscribers[ch] = true
+	return ch
+}
+
+func (n *pullNotifier) unsubscribe(ch chan struct{}) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	delete(n.subscribers, ch)
+}
+
+func (n *pullNotifier) notify() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for ch := range n.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// close will tell all streaming clients to disconnect, it's used when karma
+// is shutting down, otherwise streams would keep the HTTP server running
+func (n *pullNotifier) close() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	select {
+	case <-n.closed:
+	default:
+		close(n.closed)
+	}
+}
+
+var pullNotify = newPullNotifier()
+
+// alerts stream endpoint, server-sent events, it accepts the same query as
+// alerts.json and will send a new payload every time alert groups matching
+// the response is modified after pulling data from Alertmanager
+func alertsStream(c *gin.Context) {
+	noCache(c)
+	start := time.Now()
+
+	resp, data, _, err := alertsResponse(c, start)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		log.Infof("[%s] <%d> %s %s took %s", c.ClientIP(), http.StatusBadRequest, c.Request.Method, c.Request.RequestURI, time.Since(start))
+		return
+	}
+
+	updates := pullNotify.subscribe()
+	defer pullNotify.unsubscribe(updates)
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	log.Infof("[%s] <%d> %s %s stream started", c.ClientIP(), http.StatusOK, c.Request.Method, c.Request.RequestURI)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("alerts", string(data))
+	c.Writer.Flush()
+	// ETag ignores all timestamps, so pulls that didn't modify anything won't
+	// send a new alerts event
+	lastETag := alertsETag(resp)
+
+	for streaming := true; streaming; {
+		select {
+		case <-updates:
+			resp, data, _, err = alertsResponse(c, time.Now())
+			if err != nil {
+				log.Errorf("[%s] Failed to render alerts for stream: %s", c.ClientIP(), err)
+				streaming = false
+				break
+			}
+			if etag := alertsETag(resp); etag != lastETag {
+				lastETag = etag
+				c.SSEvent("alerts", string(data))
+				c.Writer.Flush()
+			}
+		case <-keepAlive.C:
+			c.SSEvent("ping", time.Now().UTC().Format(time.RFC3339))
+			c.Writer.Flush()
+		case <-pullNotify.closed:
+			streaming = false
+		case <-c.Request.Context().Done():
+			streaming = false
+		}
+	}
+
+	log.Infof("[%s] <%d> %s %s stream finished after %s", c.ClientIP(), http.StatusOK, c.Request.Method, c.Request.RequestURI, time.Since(start))
+}
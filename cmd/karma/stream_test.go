@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prymitive/karma/internal/config"
+	"github.com/prymitive/karma/internal/mock"
+	"github.com/prymitive/karma/internal/models"
+
+	"github.com/jarcoal/httpmock"
+)
+
+type streamEvent struct {
+	name string
+	data string
+}
+
+func readStreamEvent(t *testing.T, reader *bufio.Reader) streamEvent {
+	event := streamEvent{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read stream: %s", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			return event
+		case strings.HasPrefix(line, "event:"):
+			event.name = strings.TrimPrefix(line, "event:")
+		case strings.HasPrefix(line, "data:"):
+			event.data += strings.TrimPrefix(line, "data:")
+		}
+	}
+}
+
+func TestAlertsStream(t *testing.T) {
+	defer func(d time.Duration) { streamKeepAliveInterval = d }(streamKeepAliveInterval)
+	streamKeepAliveInterval = time.Millisecond * 100
+
+	mockConfig()
+	// unique label colors are generated using the global math/rand source,
+	// which isn't deterministic on every Go version, we don't test them here
+	config.Config.Labels.Color.Unique = []string{}
+	defer mockConfig()
+
+	for _, version := range mock.ListAllMocks() {
+		pull := func() {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			mockAlertmanagerURLs(version)
+			pullFromAlertmanager()
+		}
+
+		mockAlerts(version)
+		// first pull can detect cluster members, so only compare responses
+		// after next pulls
+		pull()
+		server := httptest.NewServer(ginTestEngine())
+
+		req, err := http.NewRequest("GET", server.URL+"/alerts/stream?q=@receiver=by-cluster-service", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("[%s] GET /alerts/stream failed: %s", version, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("[%s] GET /alerts/stream returned status %d", version, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("[%s] GET /alerts/stream returned Content-Type '%s'", version, ct)
+		}
+		if ce := resp.Header.Get("Content-Encoding"); ce != "" {
+			t.Errorf("[%s] GET /alerts/stream returned Content-Encoding '%s'", version, ce)
+		}
+
+		reader := bufio.NewReader(resp.Body)
+		event := readStreamEvent(t, reader)
+		if event.name != "alerts" {
+			t.Errorf("[%s] Expected first event to be 'alerts', got '%s'", version, event.name)
+		}
+		ur := models.AlertsResponse{}
+		if err := json.Unmarshal([]byte(event.data), &ur); err != nil {
+			t.Errorf("[%s] Failed to unmarshal stream event: %s", version, err)
+		}
+		if ur.TotalAlerts == 0 {
+			t.Errorf("[%s] Stream event has no alerts", version)
+		}
+
+		// nothing changed so there should be no new alerts event, only pings
+		pullNotify.notify()
+		event = readStreamEvent(t, reader)
+		if event.name != "ping" {
+			t.Errorf("[%s] Expected 'ping' event after pull without changes, got '%s'", version, event.name)
+		}
+
+		// pull with identical data, only timestamps will be updated
+		time.Sleep(time.Millisecond * 10)
+		pull()
+		apiCache.Flush()
+		pullNotify.notify()
+		event = readStreamEvent(t, reader)
+		if event.name != "ping" {
+			t.Errorf("[%s] Expected 'ping' event after pull with identical data, got '%s'", version, event.name)
+		}
+
+		resp.Body.Close()
+		server.Close()
+	}
+}
+
+func TestAlertsStreamInvalidRequest(t *testing.T) {
+	mockConfig()
+	r := ginTestEngine()
+	req := httptest.NewRequest("GET", "/alerts/stream?now=yesterday", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("GET /alerts/stream?now=yesterday returned status %d", resp.Code)
+	}
+}
+
+func TestPullNotifier(t *testing.T) {
+	n := newPullNotifier()
+	ch := n.subscribe()
+
+	// multiple notifications are merged into one
+	n.notify()
+	n.notify()
+	select {
+	case <-ch:
+	default:
+		t.Error("No notification received after notify()")
+	}
+	select {
+	case <-ch:
+		t.Error("Got a second notification")
+	default:
+	}
+
+	n.unsubscribe(ch)
+	n.notify()
+	select {
+	case <-ch:
+		t.Error("Got a notification after unsubscribe()")
+	default:
+	}
+
+	n.close()
+	n.close()
+	select {
+	case <-n.closed:
+	default:
+		t.Error("close() didn't close the channel")
+	}
+}
@@ -3,16 +3,40 @@ package main
 import (
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/prymitive/karma/internal/alertmanager"
 
 	log "github.com/sirupsen/logrus"
 )
 
-func pullFromAlertmanager() {
-	// always flush cache once we're done
-	defer apiCache.Flush()
+var (
+	// collectors holds a channel for every running collection loop, closing
+	// it will stop the loop
+	collectors     = map[string]chan struct{}{}
+	collectorsLock = sync.Mutex{}
+)
+
+func pullFromUpstream(am *alertmanager.Alertmanager) {
+	log.Infof("[%s] Collecting alerts and silences", am.Name)
+	fp := am.Fingerprint()
+	err := am.Pull()
+	if err != nil {
+		log.Errorf("[%s] %s", am.Name, err)
+	}
+
+	// cached responses might include data from this upstream, so we need
+	// to flush it once pull is done, even if alerts didn't change responses
+	// will still include relative time filters and timestamps of the last pull
+	apiCache.Flush()
+
+	// streaming clients only need to know about pulls that changed anything
+	if am.Fingerprint() != fp {
+		pullNotify.notify()
+	}
+}
 
+func pullFromAlertmanager() {
 	log.Info("Pulling latest alerts and silences from Alertmanager")
 
 	upstreams := alertmanager.GetAlertmanagers()
@@ -21,11 +45,7 @@ func pullFromAlertmanager() {
 
 	for _, upstream := range upstreams {
 		go func(am *alertmanager.Alertmanager) {
-			log.Infof("[%s] Collecting alerts and silences", am.Name)
-			err := am.Pull()
-			if err != nil {
-				log.Errorf("[%s] %s", am.Name, err)
-			}
+			pullFromUpstream(am)
 			wg.Done()
 		}(upstream)
 	}
@@ -36,9 +56,59 @@ func pullFromAlertmanager() {
 	runtime.GC()
 }
 
-// Tick is the background timer used to call PullFromAlertmanager
+func tickUpstream(am *alertmanager.Alertmanager, stop chan struct{}, pullFirst bool) {
+	ticker := time.NewTicker(am.Interval)
+	defer ticker.Stop()
+	if pullFirst {
+		pullFromUpstream(am)
+	}
+	for {
+		select {
+		case <-ticker.C:
+			pullFromUpstream(am)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// startCollector starts a background collection loop for given Alertmanager
+// upstream, the loop runs using the interval configured for this upstream,
+// if pullFirst is true the loop will pull once right after it starts
+func startCollector(am *alertmanager.Alertmanager, pullFirst bool) {
+	collectorsLock.Lock()
+	defer collectorsLock.Unlock()
+
+	if _, found := collectors[am.Name]; found {
+		log.Errorf("[%s] Collection loop is already running", am.Name)
+		return
+	}
+
+	log.Infof("[%s] Starting collection loop with %s interval", am.Name, am.Interval)
+	stop := make(chan struct{})
+	collectors[am.Name] = stop
+	go tickUpstream(am, stop, pullFirst)
+}
+
+// stopCollector stops the background collection loop for given Alertmanager
+// upstream, pull that is already running will be allowed to finish
+func stopCollector(name string) {
+	collectorsLock.Lock()
+	defer collectorsLock.Unlock()
+
+	stop, found := collectors[name]
+	if !found {
+		return
+	}
+
+	log.Infof("[%s] Stopping collection loop", name)
+	close(stop)
+	delete(collectors, name)
+}
+
+// Tick starts a background collection loop for every Alertmanager upstream
 func Tick() {
-	for range ticker.C {
-		pullFromAlertmanager()
+	for _, am := range alertmanager.GetAlertmanagers() {
+		startCollector(am, false)
 	}
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prymitive/karma/internal/alertmanager"
+	"github.com/prymitive/karma/internal/config"
+	"github.com/prymitive/karma/internal/mock"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestPullFromUpstreamWithoutChanges(t *testing.T) {
+	mockConfig()
+	// unique label colors are generated using the global math/rand source,
+	// which isn't deterministic on every Go version, we don't test them here
+	config.Config.Labels.Color.Unique = []string{}
+	defer mockConfig()
+
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+		am := alertmanager.GetAlertmanagerByName("default")
+
+		httpmock.Activate()
+		mockAlertmanagerURLs(version)
+		// first pull can detect cluster members, so only check next pulls
+		pullFromUpstream(am)
+
+		req := httptest.NewRequest("GET", "/alerts.json?q=@age>1h", nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		if apiCache.ItemCount() == 0 {
+			t.Errorf("[%s] Response wasn't cached", version)
+		}
+
+		updates := pullNotify.subscribe()
+		pullFromUpstream(am)
+		httpmock.DeactivateAndReset()
+
+		// responses can depend on the current time, so cache is always flushed
+		if apiCache.ItemCount() != 0 {
+			t.Errorf("[%s] Cache wasn't flushed after a pull with identical data", version)
+		}
+		select {
+		case <-updates:
+			t.Errorf("[%s] Streams were notified after a pull with identical data", version)
+		default:
+		}
+		pullNotify.unsubscribe(updates)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/sha1"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -110,6 +111,8 @@ func populateAPIFilters(matchFilters []filters.FilterT) []models.Filter {
 			Value:   filter.GetValue(),
 			Hits:    filter.GetHits(),
 			IsValid: filter.GetIsValid(),
+			Error:   filter.GetError(),
+			Terms:   populateAPIFilters(filter.GetTerms()),
 		}
 		if af.Text != "" {
 			apiFilters = append(apiFilters, af)
@@ -133,12 +136,97 @@ func authorFromHeader(c *gin.Context, header string, valueRe string) string {
 	return ""
 }
 
+// alertsETag returns the ETag value for alerts response, it's computed from
+// content fingerprints of all alert groups and all other fields except
+// timestamps, which are different on every request or pull
+func alertsETag(resp *models.AlertsResponse) string {
+	h := sha1.New()
+	for _, ag := range resp.AlertGroups {
+		fmt.Fprintf(h, "%s:%s:%d\n", ag.ID, ag.Hash, ag.TotalAlerts)
+	}
+
+	rest := *resp
+	rest.Timestamp = ""
+	rest.AlertGroups = nil
+	rest.Upstreams.Instances = make([]models.AlertmanagerAPIStatus, len(resp.Upstreams.Instances))
+	for i, instance := range resp.Upstreams.Instances {
+		instance.LastPull = time.Time{}
+		instance.CircuitBreaker.RetryAt = time.Time{}
+		rest.Upstreams.Instances[i] = instance
+	}
+	if err := json.NewEncoder(h).Encode(rest); err != nil {
+		log.Errorf("Failed to encode alerts response for ETag: %s", err)
+	}
+
+	return fmt.Sprintf("\"%x\"", h.Sum(nil))
+}
+
+// etagMatches returns true if the If-None-Match header value matches given
+// ETag, header can contain a list of ETags and weak ETags are accepted
+func etagMatches(header string, etag string) bool {
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // alerts endpoint, json, JS will query this via AJAX call
 func alerts(c *gin.Context) {
-	noCache(c)
+	// browsers are allowed to store the response, but need to revalidate it
+	// using the ETag every time
+	c.Header("Cache-Control", "no-cache")
 	start := time.Now()
+
+	resp, data, cacheStatus, err := alertsResponse(c, start)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		log.Infof("[%s] <%d> %s %s took %s", c.ClientIP(), http.StatusBadRequest, c.Request.Method, c.Request.RequestURI, time.Since(start))
+		return
+	}
+
+	etag := alertsETag(resp)
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		log.Infof("[%s %s] <%d> %s %s took %s", c.ClientIP(), cacheStatus, http.StatusNotModified, c.Request.Method, c.Request.RequestURI, time.Since(start))
+		return
+	}
+
+	c.Data(http.StatusOK, gin.MIMEJSON, data)
+	logAlertsView(c, cacheStatus, time.Since(start))
+}
+
+// alertsResponse returns the response for alerts endpoint using filters and
+// other arguments passed in the request query, along with JSON encoded copy
+// of it and the cache status, error is returned if the query is invalid
+func alertsResponse(c *gin.Context, start time.Time) (*models.AlertsResponse, []byte, string, error) {
 	ts, _ := start.UTC().MarshalText()
 
+	// filters from the selected view are added to filters passed in the query
+	filterStrings := c.QueryArray("q")
+	var view *config.ViewConfig
+	if viewName, found := c.GetQuery("view"); found && viewName != "" {
+		view = findView(viewName)
+		if view == nil {
+			return nil, nil, "", fmt.Errorf("view '%s' not found", viewName)
+		}
+		filterStrings = append(append([]string{}, view.Filters...), filterStrings...)
+	}
+
+	// optional reference time for relative filters, so links can be shared
+	// and will always show the same alerts
+	var now time.Time
+	if value, found := c.GetQuery("now"); found && value != "" {
+		var err error
+		now, err = filters.ParseTimestamp(value)
+		if err != nil {
+			return nil, nil, "", err
+		}
+	}
+
 	// initialize response object, set fields that don't require any locking
 	resp := models.AlertsResponse{}
 	resp.Status = "success"
@@ -147,14 +235,10 @@ func alerts(c *gin.Context) {
 	resp.Upstreams = getUpstreams()
 	resp.Settings = models.Settings{
 		Sorting: models.SortSettings{
-			Grid: models.GridSettings{
-				Order:   config.Config.Grid.Sorting.Order,
-				Reverse: config.Config.Grid.Sorting.Reverse,
-				Label:   config.Config.Grid.Sorting.Label,
-			},
+			Grid:         gridSettings(view),
 			ValueMapping: map[string]map[string]string{},
 		},
-		StaticColorLabels:        config.Config.Labels.Color.Static,
+		StaticColorLabels:        config.Config.LabelColors().Static,
 		AnnotationsDefaultHidden: config.Config.Annotations.Default.Hidden,
 		AnnotationsHidden:        config.Config.Annotations.Hidden,
 		AnnotationsVisible:       config.Config.Annotations.Visible,
@@ -195,13 +279,25 @@ func alerts(c *gin.Context) {
 			log.Error(err.Error())
 			panic(err)
 		}
-		c.Data(http.StatusOK, gin.MIMEJSON, newData)
-		logAlertsView(c, "HIT", time.Since(start))
-		return
+		return &newResp, newData, "HIT", nil
+	}
+
+	// invalid values would return an error, so those are never cached
+	offset, limit, err := getPageFromQuery(c)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	fields, err := getFieldsFromQuery(c)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	groupBy, err := getGroupByFromQuery(c)
+	if err != nil {
+		return nil, nil, "", err
 	}
 
 	// get filters
-	matchFilters, validFilters := getFiltersFromQuery(c.QueryArray("q"))
+	matchFilters, validFilters := getFiltersFromQuery(filterStrings, now)
 
 	// set pointers for data store objects, need a lock until end of view is reached
 	alerts := map[string]models.APIAlertGroup{}
@@ -209,6 +305,17 @@ func alerts(c *gin.Context) {
 	counters := map[string]map[string]int{}
 
 	dedupedAlerts := alertmanager.DedupAlerts()
+	if len(groupBy) > 0 {
+		dedupedAlerts = regroupAlerts(dedupedAlerts, groupBy)
+	}
+	// groups and alerts are collected from maps, so their order is random,
+	// sort both to ensure that @limit filter always returns the same alerts
+	sort.Slice(dedupedAlerts, func(i, j int) bool {
+		return dedupedAlerts[i].ID < dedupedAlerts[j].ID
+	})
+	for _, ag := range dedupedAlerts {
+		sort.Sort(ag.Alerts)
+	}
 	dedupedColors := alertmanager.DedupColors()
 
 	amNameToCluster := map[string]string{}
@@ -242,65 +349,89 @@ func alerts(c *gin.Context) {
 			results := []bool{}
 			if validFilters {
 				for _, filter := range matchFilters {
+					// alert limits are applied once group filters are checked
+					if _, ok := filter.(filters.AlertLimitT); ok {
+						continue
+					}
 					if filter.GetIsValid() {
 						match := filter.Match(&alert, matches)
 						results = append(results, match)
 					}
 				}
 			}
-			if !validFilters || (slices.BoolInSlice(results, true) && !slices.BoolInSlice(results, false)) {
-				matches++
+			if !validFilters || !slices.BoolInSlice(results, false) {
 				// we need to update fingerprints since we've modified some fields in dedup
 				// and agCopy.ContentFingerprint() depends on per alert fingerprint
 				// we update it here rather than in dedup since here we can apply it
 				// only for alerts left after filtering
 				alert.UpdateFingerprints()
 				agCopy.Alerts = append(agCopy.Alerts, alert)
+			}
+		}
 
-				countLabel(counters, "@state", alert.State)
+		// group filters can only be checked once we know which alerts are left
+		if len(agCopy.Alerts) > 0 && !matchGroupFilters(matchFilters, &agCopy) {
+			agCopy.Alerts = []models.Alert{}
+		}
 
-				countLabel(counters, "@receiver", alert.Receiver)
-				if ck, foundKey := dedupedColors["@receiver"]; foundKey {
-					if cv, foundVal := ck[alert.Receiver]; foundVal {
-						if _, found := colors["@receiver"]; !found {
-							colors["@receiver"] = map[string]models.LabelColors{}
-						}
-						colors["@receiver"][alert.Receiver] = cv
+		// @limit only counts alerts from groups that passed group filters
+		if validFilters && len(agCopy.Alerts) > 0 {
+			agCopy.Alerts = limitAlerts(matchFilters, agCopy.Alerts, matches)
+		}
+		matches += len(agCopy.Alerts)
+
+		for _, alert := range agCopy.Alerts {
+			countLabel(counters, "@state", alert.State)
+
+			countLabel(counters, "@receiver", alert.Receiver)
+			if ck, foundKey := dedupedColors["@receiver"]; foundKey {
+				if cv, foundVal := ck[alert.Receiver]; foundVal {
+					if _, found := colors["@receiver"]; !found {
+						colors["@receiver"] = map[string]models.LabelColors{}
 					}
+					colors["@receiver"][alert.Receiver] = cv
 				}
+			}
 
-				if ck, foundKey := dedupedColors["@alertmanager"]; foundKey {
-					for _, am := range alert.Alertmanager {
-						if cv, foundVal := ck[am.Name]; foundVal {
-							if _, found := colors["@alertmanager"]; !found {
-								colors["@alertmanager"] = map[string]models.LabelColors{}
-							}
-							colors["@alertmanager"][am.Name] = cv
+			if ck, foundKey := dedupedColors["@alertmanager"]; foundKey {
+				for _, am := range alert.Alertmanager {
+					if cv, foundVal := ck[am.Name]; foundVal {
+						if _, found := colors["@alertmanager"]; !found {
+							colors["@alertmanager"] = map[string]models.LabelColors{}
 						}
+						colors["@alertmanager"][am.Name] = cv
 					}
 				}
+			}
+
+			clusters := []string{}
+			for _, am := range alert.Alertmanager {
+				if !slices.StringInSlice(clusters, am.ClusterName) {
+					clusters = append(clusters, am.ClusterName)
+					countLabel(counters, "@cluster", am.ClusterName)
+				}
+			}
 
-				agCopy.StateCount[alert.State]++
+			agCopy.StateCount[alert.State]++
 
-				for _, am := range alert.Alertmanager {
-					if _, found := agCopy.AlertmanagerCount[am.Name]; !found {
-						agCopy.AlertmanagerCount[am.Name] = 1
-					} else {
-						agCopy.AlertmanagerCount[am.Name]++
-					}
+			for _, am := range alert.Alertmanager {
+				if _, found := agCopy.AlertmanagerCount[am.Name]; !found {
+					agCopy.AlertmanagerCount[am.Name] = 1
+				} else {
+					agCopy.AlertmanagerCount[am.Name]++
 				}
+			}
 
-				for key, value := range alert.Labels {
-					if keyMap, foundKey := dedupedColors[key]; foundKey {
-						if color, foundColor := keyMap[value]; foundColor {
-							if _, found := colors[key]; !found {
-								colors[key] = map[string]models.LabelColors{}
-							}
-							colors[key][value] = color
+			for key, value := range alert.Labels {
+				if keyMap, foundKey := dedupedColors[key]; foundKey {
+					if color, foundColor := keyMap[value]; foundColor {
+						if _, found := colors[key]; !found {
+							colors[key] = map[string]models.LabelColors{}
 						}
+						colors[key][value] = color
 					}
-					countLabel(counters, key, value)
 				}
+				countLabel(counters, key, value)
 			}
 		}
 
@@ -322,12 +453,14 @@ func alerts(c *gin.Context) {
 				}
 			}
 			sort.Sort(agCopy.Alerts)
+			totalAlerts := len(agCopy.Alerts)
+			limitGroup(matchFilters, &agCopy)
 			agCopy.LatestStartsAt = agCopy.FindLatestStartsAt()
 			agCopy.Hash = agCopy.ContentFingerprint()
-			apiAG := models.APIAlertGroup{AlertGroup: agCopy}
+			apiAG := models.APIAlertGroup{AlertGroup: agCopy, TotalAlerts: totalAlerts}
 			apiAG.DedupSharedMaps()
 			alerts[agCopy.ID] = apiAG
-			resp.TotalAlerts += len(agCopy.Alerts)
+			resp.TotalAlerts += totalAlerts
 		}
 
 	}
@@ -338,13 +471,23 @@ func alerts(c *gin.Context) {
 		}
 	}
 
-	resp.AlertGroups = sortAlertGroups(c, alerts)
-	resp.Silences = silences
+	resp.AlertGroups = sortAlertGroups(c, alerts, resp.Settings.Sorting.Grid)
+	resp.TotalGroups = len(resp.AlertGroups)
+	resp.AlertGroups = paginateAlertGroups(resp.AlertGroups, offset, limit)
+	pageGroups := map[string]models.APIAlertGroup{}
+	for _, ag := range resp.AlertGroups {
+		pageGroups[ag.ID] = ag
+	}
+	// silences and inhibitors are only needed for alert groups on this page,
+	// but colors and counters are for all alerts matching filters
+	resp.Silences = filterSilences(silences, resp.AlertGroups)
+	resp.Inhibitors = resolveInhibitors(dedupedAlerts, pageGroups)
 	resp.Colors = colors
 	resp.Counters = countersToLabelStats(counters)
 	resp.Filters = populateAPIFilters(matchFilters)
+	removeSkippedFields(&resp, fields)
 
-	data, err := json.Marshal(resp)
+	data, err = json.Marshal(resp)
 	if err != nil {
 		log.Error(err.Error())
 		panic(err)
@@ -356,8 +499,52 @@ func alerts(c *gin.Context) {
 	}
 	apiCache.Set(cacheKey, compressedData, -1)
 
-	c.Data(http.StatusOK, gin.MIMEJSON, data.([]byte))
-	logAlertsView(c, "MIS", time.Since(start))
+	return &resp, data.([]byte), "MIS", nil
+}
+
+// views endpoint, json, returns all views configured by the user
+func namedViews(c *gin.Context) {
+	noCache(c)
+
+	views := []models.View{}
+	for _, view := range config.Config.NamedViews() {
+		view := view // scopelint pin
+		filters := []string{}
+		filters = append(filters, view.Filters...)
+		views = append(views, models.View{
+			Name:    view.Name,
+			Title:   view.Title,
+			Filters: filters,
+			Sorting: gridSettings(&view),
+		})
+	}
+	c.JSON(http.StatusOK, views)
+}
+
+// validate endpoint, json, parses all filters passed in the query and returns
+// the reason why any of them is invalid
+func validate(c *gin.Context) {
+	noCache(c)
+	start := time.Now()
+
+	filterStrings := c.QueryArray("q")
+	if len(filterStrings) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing q=<filter> parameter"})
+		log.Infof("[%s] <%d> %s %s took %s", c.ClientIP(), http.StatusBadRequest, c.Request.Method, c.Request.RequestURI, time.Since(start))
+		return
+	}
+
+	matchFilters, _ := getFiltersFromQuery(filterStrings, time.Time{})
+	resp := models.ValidateResponse{
+		Filters: populateAPIFilters(matchFilters),
+		IsValid: true,
+	}
+	for _, filter := range matchFilters {
+		if !filter.GetIsValid() {
+			resp.IsValid = false
+		}
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 // autocomplete endpoint, json, used for filter autocomplete hints
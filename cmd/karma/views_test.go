@@ -6,7 +6,9 @@ import (
 	"html/template"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"sort"
 	"testing"
 	"time"
 
@@ -19,6 +21,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/go-cmp/cmp"
 	"github.com/jarcoal/httpmock"
 )
 
@@ -71,12 +74,7 @@ func TestIndexPrefix(t *testing.T) {
 	}
 }
 
-func mockAlerts(version string) {
-	httpmock.Activate()
-	defer httpmock.DeactivateAndReset()
-
-	apiCache = cache.New(cache.NoExpiration, 10*time.Second)
-
+func mockAlertmanagerURLs(version string) {
 	mock.RegisterURL("http://localhost/metrics", version, "metrics")
 	mock.RegisterURL("http://localhost/api/v1/status", version, "api/v1/status")
 	mock.RegisterURL("http://localhost/api/v2/status", version, "api/v2/status")
@@ -84,7 +82,15 @@ func mockAlerts(version string) {
 	mock.RegisterURL("http://localhost/api/v2/silences", version, "api/v2/silences")
 	mock.RegisterURL("http://localhost/api/v1/alerts/groups", version, "api/v1/alerts/groups")
 	mock.RegisterURL("http://localhost/api/v2/alerts/groups", version, "api/v2/alerts/groups")
+}
+
+func mockAlerts(version string) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	apiCache = cache.New(cache.NoExpiration, 10*time.Second)
 
+	mockAlertmanagerURLs(version)
 	pullFromAlertmanager()
 }
 
@@ -136,7 +142,7 @@ func TestAlerts(t *testing.T) {
 		if ur.Status != "success" {
 			t.Errorf("[%s] Invalid status in response: %s", version, ur.Status)
 		}
-		if len(ur.Counters) != 6 {
+		if len(ur.Counters) != 7 {
 			t.Errorf("[%s] Invalid number of counters in response (%d): %v", version, len(ur.Counters), ur.Counters)
 		}
 		for _, ag := range ur.AlertGroups {
@@ -158,6 +164,901 @@ func TestAlerts(t *testing.T) {
 	}
 }
 
+func TestAlertsFilterExpression(t *testing.T) {
+	mockConfig()
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+		q := url.QueryEscape("(cluster=dev | cluster=prod) !@receiver=by-name")
+		req := httptest.NewRequest("GET", "/alerts.json?q="+q, nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if resp.Code != http.StatusOK {
+			t.Errorf("GET /alerts.json returned status %d", resp.Code)
+		}
+
+		ur := models.AlertsResponse{}
+		err := json.Unmarshal(resp.Body.Bytes(), &ur)
+		if err != nil {
+			t.Errorf("Failed to unmarshal response: %s", err)
+		}
+		if len(ur.Filters) != 1 {
+			t.Fatalf("[%s] Got %d filter(s) in response, expected 1", version, len(ur.Filters))
+		}
+		if !ur.Filters[0].IsValid || len(ur.Filters[0].Terms) != 3 {
+			t.Errorf("[%s] Invalid filter in response: %v", version, ur.Filters[0])
+		}
+		if ur.Filters[0].Hits != ur.TotalAlerts {
+			t.Errorf("[%s] Filter has %d hits, expected %d", version, ur.Filters[0].Hits, ur.TotalAlerts)
+		}
+		if ur.TotalAlerts == 0 {
+			t.Errorf("[%s] No alerts returned", version)
+		}
+		for _, ag := range ur.AlertGroups {
+			for _, a := range ag.Alerts {
+				cluster := ag.Labels["cluster"]
+				if v, found := ag.Shared.Labels["cluster"]; found {
+					cluster = v
+				}
+				if v, found := a.Labels["cluster"]; found {
+					cluster = v
+				}
+				if cluster != "dev" && cluster != "prod" {
+					t.Errorf("[%s] Alert with cluster=%s returned: %v", version, cluster, a)
+				}
+				if ag.Receiver == "by-name" {
+					t.Errorf("[%s] Alert with receiver=by-name returned: %v", version, a)
+				}
+			}
+		}
+	}
+}
+
+func TestAlertsGroupFilters(t *testing.T) {
+	mockConfig()
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+
+		// every request only asks for alerts from a single receiver
+		getAlerts := func(filters ...string) models.AlertsResponse {
+			uri := "/alerts.json?q=@receiver=by-name"
+			for _, f := range filters {
+				uri += "&q=" + url.QueryEscape(f)
+			}
+			req := httptest.NewRequest("GET", uri, nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			if resp.Code != http.StatusOK {
+				t.Errorf("[%s] GET %s returned status %d", version, uri, resp.Code)
+			}
+			ur := models.AlertsResponse{}
+			if err := json.Unmarshal(resp.Body.Bytes(), &ur); err != nil {
+				t.Errorf("[%s] Failed to unmarshal response: %s", version, err)
+			}
+			return ur
+		}
+
+		all := getAlerts()
+		if all.TotalAlerts == 0 {
+			t.Errorf("[%s] No alerts returned", version)
+		}
+		largest := 0
+		for _, ag := range all.AlertGroups {
+			if ag.TotalAlerts != len(ag.Alerts) {
+				t.Errorf("[%s] Group has %d alert(s) but totalAlerts=%d", version, len(ag.Alerts), ag.TotalAlerts)
+			}
+			if len(ag.Alerts) > largest {
+				largest = len(ag.Alerts)
+			}
+		}
+
+		limited := getAlerts("@group_limit=1")
+		if limited.TotalAlerts != all.TotalAlerts {
+			t.Errorf("[%s] @group_limit=1 returned totalAlerts=%d, expected %d", version, limited.TotalAlerts, all.TotalAlerts)
+		}
+		if len(limited.AlertGroups) != len(all.AlertGroups) {
+			t.Errorf("[%s] @group_limit=1 returned %d group(s), expected %d", version, len(limited.AlertGroups), len(all.AlertGroups))
+		}
+		for _, ag := range limited.AlertGroups {
+			if len(ag.Alerts) != 1 {
+				t.Errorf("[%s] @group_limit=1 returned group with %d alert(s)", version, len(ag.Alerts))
+			}
+		}
+
+		large := getAlerts(fmt.Sprintf("@group_size>=%d", largest))
+		if len(large.AlertGroups) == 0 {
+			t.Errorf("[%s] @group_size>=%d returned no groups", version, largest)
+		}
+		total := 0
+		for _, ag := range large.AlertGroups {
+			if len(ag.Alerts) < largest {
+				t.Errorf("[%s] @group_size>=%d returned group with %d alert(s)", version, largest, len(ag.Alerts))
+			}
+			total += len(ag.Alerts)
+		}
+		if large.TotalAlerts != total {
+			t.Errorf("[%s] @group_size>=%d returned totalAlerts=%d, expected %d", version, largest, large.TotalAlerts, total)
+		}
+
+		// @limit is applied after group filters, so it doesn't matter in which
+		// order groups are checked, repeat it since that order is random
+		if largest > 1 {
+			for i := 0; i < 5; i++ {
+				apiCache.Flush()
+				first := getAlerts(fmt.Sprintf("@group_size>=%d", largest), "@limit=1")
+				if first.TotalAlerts != 1 {
+					t.Errorf("[%s] @group_size>=%d @limit=1 returned totalAlerts=%d, expected 1", version, largest, first.TotalAlerts)
+				}
+			}
+		}
+
+		// groups and alerts are sorted before applying @limit, so it must
+		// always return the same alerts
+		var limitedIDs []string
+		for i := 0; i < 10; i++ {
+			apiCache.Flush()
+			ids := []string{}
+			for _, ag := range getAlerts("@limit=3").AlertGroups {
+				for _, a := range ag.Alerts {
+					ids = append(ids, fmt.Sprintf("%s/%s", ag.ID, a.LabelsFingerprint()))
+				}
+			}
+			sort.Strings(ids)
+			if i > 0 {
+				if diff := cmp.Diff(limitedIDs, ids); diff != "" {
+					t.Errorf("[%s] @limit=3 returned different alerts (-want +got):\n%s", version, diff)
+				}
+			}
+			limitedIDs = ids
+		}
+
+		suppressed := getAlerts("@group_state=suppressed")
+		for _, ag := range suppressed.AlertGroups {
+			for _, a := range ag.Alerts {
+				if a.State != models.AlertStateSuppressed {
+					t.Errorf("[%s] @group_state=suppressed returned alert with state=%s", version, a.State)
+				}
+			}
+		}
+	}
+}
+
+func TestAlertsNow(t *testing.T) {
+	mockConfig()
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+
+		for _, nowTest := range []struct {
+			now      string
+			noAlerts bool
+		}{
+			{now: "", noAlerts: false},
+			{now: "2000-01-01T00:00Z", noAlerts: true},
+			{now: "2100-01-01T00:00:00Z", noAlerts: false},
+		} {
+			uri := "/alerts.json?q=" + url.QueryEscape("@age>1h")
+			if nowTest.now != "" {
+				uri += "&now=" + url.QueryEscape(nowTest.now)
+			}
+			req := httptest.NewRequest("GET", uri, nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			if resp.Code != http.StatusOK {
+				t.Errorf("[%s] GET %s returned status %d", version, uri, resp.Code)
+			}
+			ur := models.AlertsResponse{}
+			if err := json.Unmarshal(resp.Body.Bytes(), &ur); err != nil {
+				t.Errorf("[%s] Failed to unmarshal response: %s", version, err)
+			}
+			if nowTest.noAlerts != (ur.TotalAlerts == 0) {
+				t.Errorf("[%s] GET %s returned %d alert(s)", version, uri, ur.TotalAlerts)
+			}
+		}
+
+		req := httptest.NewRequest("GET", "/alerts.json?now=yesterday", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("[%s] GET /alerts.json?now=yesterday returned status %d", version, resp.Code)
+		}
+	}
+}
+
+func TestAlertsETag(t *testing.T) {
+	mockConfig()
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+		uri := "/alerts.json?q=@receiver=by-cluster-service"
+
+		etags := []string{}
+		for i := 0; i < 2; i++ {
+			// first request will render the response, second is served from cache
+			req := httptest.NewRequest("GET", uri, nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			if resp.Code != http.StatusOK {
+				t.Errorf("[%s] GET %s returned status %d", version, uri, resp.Code)
+			}
+			if cc := resp.Header().Get("Cache-Control"); cc != "no-cache" {
+				t.Errorf("[%s] GET %s returned Cache-Control '%s'", version, uri, cc)
+			}
+			etags = append(etags, resp.Header().Get("ETag"))
+			time.Sleep(time.Millisecond * 10)
+		}
+		if etags[0] == "" || etags[0] != etags[1] {
+			t.Errorf("[%s] GET %s returned different ETags: %v", version, uri, etags)
+		}
+
+		for _, etagTest := range []struct {
+			ifNoneMatch   string
+			acceptGzip    bool
+			expectedCode  int
+			expectedEmpty bool
+		}{
+			{ifNoneMatch: etags[0], expectedCode: http.StatusNotModified, expectedEmpty: true},
+			{ifNoneMatch: etags[0], acceptGzip: true, expectedCode: http.StatusNotModified},
+			{ifNoneMatch: "W/" + etags[0], expectedCode: http.StatusNotModified, expectedEmpty: true},
+			{ifNoneMatch: "\"foo\", " + etags[0], expectedCode: http.StatusNotModified, expectedEmpty: true},
+			{ifNoneMatch: "*", expectedCode: http.StatusNotModified, expectedEmpty: true},
+			{ifNoneMatch: "\"foo\"", expectedCode: http.StatusOK},
+			{ifNoneMatch: "", expectedCode: http.StatusOK},
+		} {
+			req := httptest.NewRequest("GET", uri, nil)
+			req.Header.Set("If-None-Match", etagTest.ifNoneMatch)
+			if etagTest.acceptGzip {
+				req.Header.Set("Accept-Encoding", "gzip")
+			}
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			if resp.Code != etagTest.expectedCode {
+				t.Errorf("[%s] GET %s with If-None-Match '%s' returned status %d, expected %d", version, uri, etagTest.ifNoneMatch, resp.Code, etagTest.expectedCode)
+			}
+			if etagTest.expectedEmpty && resp.Body.Len() > 0 {
+				t.Errorf("[%s] GET %s with If-None-Match '%s' returned non-empty body", version, uri, etagTest.ifNoneMatch)
+			}
+			if etag := resp.Header().Get("ETag"); etag != etags[0] {
+				t.Errorf("[%s] GET %s with If-None-Match '%s' returned ETag '%s', expected '%s'", version, uri, etagTest.ifNoneMatch, etag, etags[0])
+			}
+		}
+
+		// different filters should give a different ETag
+		req := httptest.NewRequest("GET", "/alerts.json?q=@receiver=by-name", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if etag := resp.Header().Get("ETag"); etag == etags[0] {
+			t.Errorf("[%s] GET /alerts.json?q=@receiver=by-name returned the same ETag '%s'", version, etag)
+		}
+	}
+}
+
+func TestAlertsETagAfterPull(t *testing.T) {
+	mockConfig()
+	// unique label colors are generated using the global math/rand source,
+	// which isn't deterministic on every Go version, we don't test them here
+	config.Config.Labels.Color.Unique = []string{}
+	defer mockConfig()
+
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+		uri := "/alerts.json?q=@receiver=by-cluster-service"
+
+		httpmock.Activate()
+		mockAlertmanagerURLs(version)
+		// first pull can detect cluster members, so only compare responses
+		// after next pulls
+		pullFromAlertmanager()
+
+		etags := []string{}
+		for i := 0; i < 2; i++ {
+			apiCache.Flush()
+			req := httptest.NewRequest("GET", uri, nil)
+			if i > 0 {
+				req.Header.Set("If-None-Match", etags[0])
+			}
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			etags = append(etags, resp.Header().Get("ETag"))
+			if i > 0 && resp.Code != http.StatusNotModified {
+				t.Errorf("[%s] GET %s after a pull with identical data returned status %d, expected %d", version, uri, resp.Code, http.StatusNotModified)
+			}
+
+			time.Sleep(time.Millisecond * 10)
+			pullFromAlertmanager()
+		}
+		httpmock.DeactivateAndReset()
+
+		if etags[0] == "" || etags[0] != etags[1] {
+			t.Errorf("[%s] GET %s returned different ETags after a pull with identical data: %v", version, uri, etags)
+		}
+	}
+}
+
+func TestAlertsPagination(t *testing.T) {
+	mockConfig()
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+
+		getAlerts := func(query string) models.AlertsResponse {
+			uri := "/alerts.json?q=@receiver=by-name&" + query
+			req := httptest.NewRequest("GET", uri, nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			if resp.Code != http.StatusOK {
+				t.Errorf("[%s] GET %s returned status %d", version, uri, resp.Code)
+			}
+			ur := models.AlertsResponse{}
+			if err := json.Unmarshal(resp.Body.Bytes(), &ur); err != nil {
+				t.Errorf("[%s] Failed to unmarshal response: %s", version, err)
+			}
+			return ur
+		}
+		groupIDs := func(groups []models.APIAlertGroup) []string {
+			ids := []string{}
+			for _, ag := range groups {
+				ids = append(ids, ag.ID)
+			}
+			return ids
+		}
+
+		all := getAlerts("")
+		if all.TotalGroups != len(all.AlertGroups) || all.TotalGroups < 4 {
+			t.Fatalf("[%s] Got %d groups with totalGroups=%d", version, len(all.AlertGroups), all.TotalGroups)
+		}
+		allIDs := groupIDs(all.AlertGroups)
+
+		for _, pageTest := range []struct {
+			query    string
+			expected []string
+		}{
+			{query: "limit=2", expected: allIDs[:2]},
+			{query: "offset=2&limit=2", expected: allIDs[2:4]},
+			{query: "offset=1", expected: allIDs[1:]},
+			{query: "offset=0&limit=0", expected: allIDs},
+			{query: fmt.Sprintf("offset=%d", len(allIDs)), expected: []string{}},
+			{query: "offset=1000&limit=10", expected: []string{}},
+		} {
+			ur := getAlerts(pageTest.query)
+			if diff := cmp.Diff(pageTest.expected, groupIDs(ur.AlertGroups)); diff != "" {
+				t.Errorf("[%s] Wrong groups returned for %s (-want +got):\n%s", version, pageTest.query, diff)
+			}
+			if ur.TotalGroups != all.TotalGroups {
+				t.Errorf("[%s] Got totalGroups=%d for %s, expected %d", version, ur.TotalGroups, pageTest.query, all.TotalGroups)
+			}
+			if ur.TotalAlerts != all.TotalAlerts {
+				t.Errorf("[%s] Got totalAlerts=%d for %s, expected %d", version, ur.TotalAlerts, pageTest.query, all.TotalAlerts)
+			}
+			for cluster, silences := range ur.Silences {
+				for silenceID := range silences {
+					if _, found := all.Silences[cluster][silenceID]; !found {
+						t.Errorf("[%s] Unknown silence %s/%s returned for %s", version, cluster, silenceID, pageTest.query)
+					}
+				}
+			}
+		}
+
+		if len(getAlerts("offset=1000").Silences) != len(all.Silences) {
+			t.Errorf("[%s] Silences map should have all clusters", version)
+		}
+		for _, silences := range getAlerts("offset=1000").Silences {
+			if len(silences) > 0 {
+				t.Errorf("[%s] Got silences for an empty page: %v", version, silences)
+			}
+		}
+
+		for _, query := range []string{"offset=-1", "limit=-5", "limit=abc", "offset=1.5"} {
+			uri := "/alerts.json?" + query
+			req := httptest.NewRequest("GET", uri, nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			if resp.Code != http.StatusBadRequest {
+				t.Errorf("[%s] GET %s returned status %d", version, uri, resp.Code)
+			}
+		}
+	}
+}
+
+func TestAlertsFields(t *testing.T) {
+	mockConfig()
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+
+		for _, fieldsTest := range []struct {
+			query  string
+			fields []string
+		}{
+			{query: "", fields: alertsResponseFields},
+			{query: "&fields=", fields: []string{}},
+			{query: "&fields=counters", fields: []string{"counters"}},
+			{query: "&fields=counters,colors", fields: []string{"counters", "colors"}},
+			{query: "&fields=silences&fields=annotations", fields: []string{"annotations", "silences"}},
+			{query: "&fields=" + url.QueryEscape("inhibitors, silences"), fields: []string{"inhibitors", "silences"}},
+		} {
+			uri := "/alerts.json?q=@receiver=by-name" + fieldsTest.query
+			req := httptest.NewRequest("GET", uri, nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			if resp.Code != http.StatusOK {
+				t.Errorf("[%s] GET %s returned status %d", version, uri, resp.Code)
+			}
+			ur := models.AlertsResponse{}
+			if err := json.Unmarshal(resp.Body.Bytes(), &ur); err != nil {
+				t.Errorf("[%s] Failed to unmarshal response: %s", version, err)
+			}
+			if len(ur.AlertGroups) == 0 {
+				t.Errorf("[%s] GET %s returned no groups", version, uri)
+			}
+
+			annotations := 0
+			for _, ag := range ur.AlertGroups {
+				annotations += len(ag.Shared.Annotations)
+				for _, alert := range ag.Alerts {
+					annotations += len(alert.Annotations)
+				}
+			}
+			for field, count := range map[string]int{
+				"annotations": annotations,
+				"colors":      len(ur.Colors),
+				"counters":    len(ur.Counters),
+				"silences":    len(ur.Silences),
+			} {
+				if slices.StringInSlice(fieldsTest.fields, field) != (count > 0) {
+					t.Errorf("[%s] GET %s returned %d %s", version, uri, count, field)
+				}
+			}
+			if !slices.StringInSlice(fieldsTest.fields, "inhibitors") && len(ur.Inhibitors) > 0 {
+				t.Errorf("[%s] GET %s returned %d inhibitors", version, uri, len(ur.Inhibitors))
+			}
+		}
+
+		uri := "/alerts.json?fields=labels"
+		req := httptest.NewRequest("GET", uri, nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("[%s] GET %s returned status %d", version, uri, resp.Code)
+		}
+	}
+}
+
+func TestAlertsGroupBy(t *testing.T) {
+	mockConfig()
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+
+		getAlerts := func(query string, expectedCode int) models.AlertsResponse {
+			uri := "/alerts.json?" + query
+			req := httptest.NewRequest("GET", uri, nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			if resp.Code != expectedCode {
+				t.Errorf("[%s] GET %s returned status %d", version, uri, resp.Code)
+			}
+			ur := models.AlertsResponse{}
+			if err := json.Unmarshal(resp.Body.Bytes(), &ur); err != nil {
+				t.Errorf("[%s] Failed to unmarshal response: %s", version, err)
+			}
+			return ur
+		}
+		groupKeys := func(ur models.AlertsResponse) []string {
+			keys := []string{}
+			for _, ag := range ur.AlertGroups {
+				keys = append(keys, fmt.Sprintf("%s/%v/%d", ag.Receiver, ag.Labels, len(ag.Alerts)))
+			}
+			sort.Strings(keys)
+			return keys
+		}
+
+		all := getAlerts("", http.StatusOK)
+		receivers := map[string]bool{}
+		// alerts routed to multiple receivers are only counted once when
+		// regrouping without @receiver
+		uniqueAlerts := map[string]bool{}
+		for _, ag := range all.AlertGroups {
+			receivers[ag.Receiver] = true
+			for _, alert := range ag.Alerts {
+				labels := map[string]string{}
+				for _, ls := range []map[string]string{ag.Labels, ag.Shared.Labels, alert.Labels} {
+					for k, v := range ls {
+						labels[k] = v
+					}
+				}
+				uniqueAlerts[fmt.Sprintf("%v", labels)] = true
+			}
+		}
+
+		byReceiver := getAlerts("groupBy=@receiver", http.StatusOK)
+		if len(byReceiver.AlertGroups) != len(receivers) {
+			t.Errorf("[%s] Got %d groups for groupBy=@receiver, expected %d", version, len(byReceiver.AlertGroups), len(receivers))
+		}
+		for _, ag := range byReceiver.AlertGroups {
+			if !receivers[ag.Receiver] || len(ag.Labels) != 0 {
+				t.Errorf("[%s] Invalid group for groupBy=@receiver: receiver=%s labels=%v", version, ag.Receiver, ag.Labels)
+			}
+		}
+		if byReceiver.TotalAlerts != all.TotalAlerts {
+			t.Errorf("[%s] Got %d alerts for groupBy=@receiver, expected %d", version, byReceiver.TotalAlerts, all.TotalAlerts)
+		}
+
+		byCluster := getAlerts("groupBy=cluster", http.StatusOK)
+		clusters := map[string]bool{}
+		for _, ag := range byCluster.AlertGroups {
+			if len(ag.Labels) > 1 {
+				t.Errorf("[%s] Invalid labels for groupBy=cluster: %v", version, ag.Labels)
+			}
+			if clusters[ag.Labels["cluster"]] {
+				t.Errorf("[%s] Duplicated group for groupBy=cluster: %v", version, ag.Labels)
+			}
+			clusters[ag.Labels["cluster"]] = true
+			for _, alert := range ag.Alerts {
+				if _, found := alert.Labels["cluster"]; found {
+					t.Errorf("[%s] Grouping label wasn't removed from alert: %v", version, alert.Labels)
+				}
+			}
+		}
+		if byCluster.TotalAlerts != len(uniqueAlerts) {
+			t.Errorf("[%s] Got %d alerts for groupBy=cluster, expected %d", version, byCluster.TotalAlerts, len(uniqueAlerts))
+		}
+
+		// shared labels are still moved out of alerts
+		for _, ag := range getAlerts("groupBy=alertname", http.StatusOK).AlertGroups {
+			if ag.Receiver != "" {
+				t.Errorf("[%s] Group for groupBy=alertname with alerts for multiple receivers has receiver '%s'", version, ag.Receiver)
+			}
+			if len(ag.Alerts) > 1 && len(ag.Shared.Labels) == 0 {
+				t.Errorf("[%s] Group %v with %d alerts has no shared labels", version, ag.Labels, len(ag.Alerts))
+			}
+			// alerts routed to multiple receivers are only included once
+			labels := map[string]bool{}
+			for _, alert := range ag.Alerts {
+				key := fmt.Sprintf("%v", alert.Labels)
+				if labels[key] {
+					t.Errorf("[%s] Group %v for groupBy=alertname has duplicated alert %s", version, ag.Labels, key)
+				}
+				labels[key] = true
+			}
+			if ag.TotalAlerts != len(ag.Alerts) {
+				t.Errorf("[%s] Group %v for groupBy=alertname has %d alert(s) but totalAlerts=%d", version, ag.Labels, len(ag.Alerts), ag.TotalAlerts)
+			}
+		}
+
+		for _, query := range []string{"groupBy=@alertmanager", "groupBy=@cluster"} {
+			ur := getAlerts(query, http.StatusOK)
+			if len(ur.AlertGroups) == 0 {
+				t.Errorf("[%s] No groups for %s", version, query)
+			}
+			for _, ag := range ur.AlertGroups {
+				if len(ag.Labels) != 0 {
+					t.Errorf("[%s] Invalid labels for %s: %v", version, query, ag.Labels)
+				}
+				values := map[string]bool{}
+				for _, alert := range ag.Alerts {
+					for _, am := range alert.Alertmanager {
+						if query == "groupBy=@alertmanager" {
+							values[am.Name] = true
+						} else {
+							values[am.ClusterName] = true
+						}
+					}
+				}
+				if len(values) != 1 {
+					t.Errorf("[%s] Group for %s has alerts from multiple upstreams: %v", version, query, values)
+				}
+			}
+		}
+
+		if diff := cmp.Diff(groupKeys(getAlerts("groupBy=job&groupBy=@receiver", http.StatusOK)), groupKeys(getAlerts("groupBy="+url.QueryEscape("job, @receiver"), http.StatusOK))); diff != "" {
+			t.Errorf("[%s] groupBy values passed as a list returned different groups (-want +got):\n%s", version, diff)
+		}
+		if diff := cmp.Diff(groupKeys(all), groupKeys(getAlerts("groupBy=", http.StatusOK))); diff != "" {
+			t.Errorf("[%s] Empty groupBy returned different groups (-want +got):\n%s", version, diff)
+		}
+
+		getAlerts("groupBy=@state", http.StatusBadRequest)
+	}
+}
+
+func TestRegroupAlertsState(t *testing.T) {
+	alert := models.Alert{
+		Labels:   map[string]string{"alertname": "Fake"},
+		State:    models.AlertStateActive,
+		Receiver: "by-name",
+		Alertmanager: []models.AlertmanagerInstance{
+			{Name: "am1", ClusterName: "c1", State: models.AlertStateActive},
+			{Name: "am2", ClusterName: "c2", State: models.AlertStateSuppressed, SilencedBy: []string{"1"}},
+			{Name: "am3", ClusterName: "c2", State: models.AlertStateUnprocessed},
+		},
+	}
+	groups := []models.AlertGroup{{ID: "1", Receiver: "by-name", Alerts: models.AlertList{alert}}}
+
+	expected := map[string]string{
+		"am1": models.AlertStateActive,
+		"am2": models.AlertStateSuppressed,
+		"am3": models.AlertStateUnprocessed,
+		"c1":  models.AlertStateActive,
+		"c2":  models.AlertStateSuppressed,
+	}
+	for _, groupBy := range []string{"@alertmanager", "@cluster"} {
+		regrouped := regroupAlerts(groups, []string{groupBy})
+		for _, ag := range regrouped {
+			if ag.Receiver != "by-name" {
+				t.Errorf("Invalid receiver '%s' for groupBy=%s", ag.Receiver, groupBy)
+			}
+			if len(ag.Alerts) != 1 {
+				t.Errorf("Got %d alerts for groupBy=%s, expected 1", len(ag.Alerts), groupBy)
+				continue
+			}
+			am := ag.Alerts[0].Alertmanager[0]
+			key := am.Name
+			if groupBy == "@cluster" {
+				key = am.ClusterName
+			}
+			if ag.Alerts[0].State != expected[key] {
+				t.Errorf("Got state '%s' for %s=%s, expected '%s'", ag.Alerts[0].State, groupBy, key, expected[key])
+			}
+			delete(expected, key)
+		}
+	}
+	if len(expected) != 0 {
+		t.Errorf("Missing groups: %v", expected)
+	}
+}
+
+func TestRegroupAlertsMultipleReceivers(t *testing.T) {
+	newAlert := func(instance, receiver string, ams ...models.AlertmanagerInstance) models.Alert {
+		alert := models.Alert{
+			Labels:       map[string]string{"alertname": "Fake", "instance": instance},
+			State:        models.AlertStateActive,
+			Receiver:     receiver,
+			Alertmanager: ams,
+		}
+		alert.UpdateFingerprints()
+		return alert
+	}
+	am1 := models.AlertmanagerInstance{Name: "am1", State: models.AlertStateActive}
+	am2 := models.AlertmanagerInstance{Name: "am2", State: models.AlertStateActive}
+
+	// alert for instance a is routed to both receivers
+	groups := []models.AlertGroup{
+		{ID: "1", Receiver: "by-name", Alerts: models.AlertList{
+			newAlert("a", "by-name", am1),
+			newAlert("b", "by-name", am1),
+		}},
+		{ID: "2", Receiver: "by-cluster", Alerts: models.AlertList{
+			newAlert("a", "by-cluster", am1, am2),
+		}},
+	}
+
+	regrouped := regroupAlerts(groups, []string{"alertname"})
+	if len(regrouped) != 1 {
+		t.Fatalf("Got %d groups for groupBy=alertname, expected 1", len(regrouped))
+	}
+	ag := regrouped[0]
+	if ag.Receiver != "" {
+		t.Errorf("Group with alerts for multiple receivers has receiver '%s'", ag.Receiver)
+	}
+	if len(ag.Alerts) != 2 {
+		t.Fatalf("Got %d alerts for groupBy=alertname, expected 2", len(ag.Alerts))
+	}
+	for _, alert := range ag.Alerts {
+		if alert.Labels["instance"] != "a" {
+			continue
+		}
+		if alert.Receiver != "by-cluster" {
+			t.Errorf("Merged alert has receiver '%s', expected 'by-cluster'", alert.Receiver)
+		}
+		names := []string{}
+		for _, am := range alert.Alertmanager {
+			names = append(names, am.Name)
+		}
+		if diff := cmp.Diff([]string{"am1", "am2"}, names); diff != "" {
+			t.Errorf("Merged alert has wrong Alertmanager instances (-want +got):\n%s", diff)
+		}
+	}
+
+	// alerts are only merged if they end up in the same group
+	regrouped = regroupAlerts(groups, []string{"@receiver", "alertname"})
+	total := 0
+	for _, ag := range regrouped {
+		total += len(ag.Alerts)
+	}
+	if len(regrouped) != 2 || total != 3 {
+		t.Errorf("Got %d groups with %d alerts for groupBy=@receiver,alertname, expected 2 groups with 3 alerts", len(regrouped), total)
+	}
+}
+
+func TestRegroupAlertsOrder(t *testing.T) {
+	groups := []models.AlertGroup{}
+	for i, instance := range []string{"a", "b", "c"} {
+		alert := models.Alert{
+			Labels:   map[string]string{"alertname": "Fake", "instance": instance},
+			State:    models.AlertStateActive,
+			Receiver: "by-name",
+			StartsAt: time.Date(2019, 5, 1, 10, i, 0, 0, time.UTC),
+		}
+		alert.UpdateFingerprints()
+		groups = append(groups, models.AlertGroup{ID: instance, Receiver: "by-name", Alerts: models.AlertList{alert}})
+	}
+	reversed := []models.AlertGroup{groups[2], groups[1], groups[0]}
+
+	instances := func(groups []models.AlertGroup) []string {
+		values := []string{}
+		for _, ag := range regroupAlerts(groups, []string{"alertname"}) {
+			for _, alert := range ag.Alerts {
+				values = append(values, alert.Labels["instance"])
+			}
+		}
+		return values
+	}
+	if diff := cmp.Diff(instances(groups), instances(reversed)); diff != "" {
+		t.Errorf("Regrouped alerts order depends on the order of groups (-want +got):\n%s", diff)
+	}
+}
+
+func TestResolveInhibitors(t *testing.T) {
+	source := models.Alert{
+		State:  models.AlertStateActive,
+		Labels: map[string]string{"alertname": "Down", "severity": "critical"},
+		Alertmanager: []models.AlertmanagerInstance{
+			{Name: "am1", State: models.AlertStateActive},
+		},
+		Fingerprint: "1111111111111111",
+	}
+	target := models.Alert{
+		State:  models.AlertStateSuppressed,
+		Labels: map[string]string{"alertname": "Down", "severity": "warning"},
+		Alertmanager: []models.AlertmanagerInstance{
+			{Name: "am1", State: models.AlertStateSuppressed, InhibitedBy: []string{"1111111111111111", "2222222222222222"}},
+		},
+		InhibitedBy: []string{"1111111111111111", "2222222222222222"},
+		Fingerprint: "3333333333333333",
+	}
+	allGroups := []models.AlertGroup{
+		{ID: "1", Alerts: models.AlertList{source, target}},
+	}
+	groups := map[string]models.APIAlertGroup{
+		"1": {AlertGroup: models.AlertGroup{ID: "1", Alerts: models.AlertList{target}}},
+	}
+
+	inhibitors := resolveInhibitors(allGroups, groups)
+	expected := map[string]map[string]string{
+		"1111111111111111": source.Labels,
+	}
+	if diff := cmp.Diff(expected, inhibitors); diff != "" {
+		t.Errorf("Wrong inhibitors returned (-want +got):\n%s", diff)
+	}
+}
+
+func TestViews(t *testing.T) {
+	mockConfig()
+	reverse := false
+	view := config.ViewConfig{
+		Name:    "dev",
+		Title:   "Dev cluster",
+		Filters: []string{"cluster=dev"},
+	}
+	view.Sorting.Order = "label"
+	view.Sorting.Reverse = &reverse
+	view.Sorting.Label = "instance"
+	config.Config.Views = []config.ViewConfig{view}
+	defer func() {
+		config.Config.Views = []config.ViewConfig{}
+	}()
+
+	r := ginTestEngine()
+	req := httptest.NewRequest("GET", "/views.json", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("GET /views.json returned status %d", resp.Code)
+	}
+	views := []models.View{}
+	if err := json.Unmarshal(resp.Body.Bytes(), &views); err != nil {
+		t.Fatalf("Failed to unmarshal response: %s", err)
+	}
+	expected := []models.View{
+		{
+			Name:    "dev",
+			Title:   "Dev cluster",
+			Filters: []string{"cluster=dev"},
+			Sorting: models.GridSettings{Order: "label", Reverse: false, Label: "instance"},
+		},
+	}
+	if diff := cmp.Diff(expected, views); diff != "" {
+		t.Errorf("Wrong views returned (-want +got):\n%s", diff)
+	}
+
+	for _, version := range mock.ListAllMocks() {
+		mockAlerts(version)
+		r := ginTestEngine()
+		req := httptest.NewRequest("GET", "/alerts.json?view=dev&q=@receiver=by-cluster-service", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if resp.Code != http.StatusOK {
+			t.Errorf("[%s] GET /alerts.json?view=dev returned status %d", version, resp.Code)
+		}
+		ur := models.AlertsResponse{}
+		if err := json.Unmarshal(resp.Body.Bytes(), &ur); err != nil {
+			t.Fatalf("[%s] Failed to unmarshal response: %s", version, err)
+		}
+		if len(ur.Filters) != 2 || ur.Filters[0].Text != "cluster=dev" || ur.Filters[1].Text != "@receiver=by-cluster-service" {
+			t.Errorf("[%s] Invalid filters in response: %v", version, ur.Filters)
+		}
+		if ur.Settings.Sorting.Grid != expected[0].Sorting {
+			t.Errorf("[%s] Invalid sort settings in response: %v", version, ur.Settings.Sorting.Grid)
+		}
+		if ur.TotalAlerts == 0 {
+			t.Errorf("[%s] No alerts returned", version)
+		}
+		for _, ag := range ur.AlertGroups {
+			if ag.Receiver != "by-cluster-service" {
+				t.Errorf("[%s] Alert group with receiver=%s returned", version, ag.Receiver)
+			}
+		}
+
+		req = httptest.NewRequest("GET", "/alerts.json?view=foo", nil)
+		resp = httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("[%s] GET /alerts.json?view=foo returned status %d", version, resp.Code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	mockConfig()
+	r := ginTestEngine()
+
+	req := httptest.NewRequest("GET", "/validate.json", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("GET /validate.json returned status %d", resp.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/validate.json?q=@state=active&q=@age=1h", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("GET /validate.json returned status %d", resp.Code)
+	}
+	vr := models.ValidateResponse{}
+	if err := json.Unmarshal(resp.Body.Bytes(), &vr); err != nil {
+		t.Fatalf("Failed to unmarshal response: %s", err)
+	}
+	expected := models.ValidateResponse{
+		Filters: []models.Filter{
+			{
+				Text:    "@state=active",
+				Name:    "@state",
+				Matcher: "=",
+				Value:   "active",
+				IsValid: true,
+				Terms:   []models.Filter{},
+			},
+			{
+				Text:  "@age=1h",
+				Value: "@age=1h",
+				Error: &models.FilterError{
+					Code:    "unsupported_operator",
+					Message: "operator '=' is not supported by @age filter, supported operators: < > <= >=",
+				},
+				Terms: []models.Filter{},
+			},
+		},
+		IsValid: false,
+	}
+	if diff := cmp.Diff(expected, vr); diff != "" {
+		t.Errorf("Wrong validate response (-want +got):\n%s", diff)
+	}
+}
+
 func TestValidateAllAlerts(t *testing.T) {
 	mockConfig()
 	for _, version := range mock.ListAllMocks() {
@@ -206,6 +1107,12 @@ var acTests = []acTestCase{
 			"alertname!=Host_Down",
 			"alertname!=HTTP_Probe_Failed",
 			"alertname!=Free_Disk_Space_Too_Low",
+			"@missing=alertname",
+			"@has=alertname",
+			"@annotation.alert=Memory usage exceeding threshold",
+			"@annotation.alert=Less than 10% disk space is free",
+			"@annotation.alert!=Memory usage exceeding threshold",
+			"@annotation.alert!=Less than 10% disk space is free",
 			"@alertmanager=default",
 			"@alertmanager!=default",
 		},
@@ -221,6 +1128,12 @@ var acTests = []acTestCase{
 			"alertname!=Host_Down",
 			"alertname!=HTTP_Probe_Failed",
 			"alertname!=Free_Disk_Space_Too_Low",
+			"@missing=alertname",
+			"@has=alertname",
+			"@annotation.alert=Memory usage exceeding threshold",
+			"@annotation.alert=Less than 10% disk space is free",
+			"@annotation.alert!=Memory usage exceeding threshold",
+			"@annotation.alert!=Less than 10% disk space is free",
 			"@alertmanager=default",
 			"@alertmanager!=default",
 		},
@@ -236,6 +1149,8 @@ var acTests = []acTestCase{
 			"alertname!=Host_Down",
 			"alertname!=HTTP_Probe_Failed",
 			"alertname!=Free_Disk_Space_Too_Low",
+			"@missing=alertname",
+			"@has=alertname",
 		},
 	},
 	{
@@ -249,6 +1164,8 @@ var acTests = []acTestCase{
 			"alertname!=Host_Down",
 			"alertname!=HTTP_Probe_Failed",
 			"alertname!=Free_Disk_Space_Too_Low",
+			"@missing=alertname",
+			"@has=alertname",
 		},
 	},
 	{
@@ -256,6 +1173,10 @@ var acTests = []acTestCase{
 		Results: []string{
 			"alertname=HTTP_Probe_Failed",
 			"alertname!=HTTP_Probe_Failed",
+			"@annotation.url=http://localhost/example.html",
+			"@annotation.url!=http://localhost/example.html",
+			"@annotation.dashboard=http://localhost/dashboard.html",
+			"@annotation.dashboard!=http://localhost/dashboard.html",
 		},
 	},
 	{
@@ -0,0 +1,101 @@
+package alertmanager
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// BreakerClosed means that requests are sent to the Alertmanager
+	BreakerClosed = "closed"
+	// BreakerOpen means that the Alertmanager failed too many times in a row
+	// and we're skipping all pulls until cool-down passes
+	BreakerOpen = "open"
+	// BreakerHalfOpen means that cool-down passed and we will send a single
+	// request to check if the Alertmanager recovered
+	BreakerHalfOpen = "half-open"
+)
+
+// BreakerStates is the list of all possible circuit breaker states
+var BreakerStates = []string{BreakerClosed, BreakerOpen, BreakerHalfOpen}
+
+// circuitBreaker tracks consecutive pull failures, it doesn't have its own
+// lock, all access must be protected by the Alertmanager lock
+type circuitBreaker struct {
+	failures  int
+	openedAt  time.Time
+	lastError string
+}
+
+func (cb *circuitBreaker) state(threshold int, cooldown time.Duration) string {
+	if threshold <= 0 || cb.failures < threshold {
+		return BreakerClosed
+	}
+	if time.Since(cb.openedAt) < cooldown {
+		return BreakerOpen
+	}
+	return BreakerHalfOpen
+}
+
+// breakerAllows returns an error if pulls are currently blocked by the circuit
+// breaker
+func (am *Alertmanager) breakerAllows() error {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	if am.breaker.state(am.BreakerFailures, am.BreakerCooldown) != BreakerOpen {
+		return nil
+	}
+	return fmt.Errorf("circuit breaker open after %d consecutive failure(s), skipping pulls until %s, last error: %s",
+		am.breaker.failures, am.breaker.openedAt.Add(am.BreakerCooldown).Format(time.RFC3339), am.breaker.lastError)
+}
+
+// breakerFailure records a failed pull and opens the circuit breaker if we
+// reached the consecutive failures threshold
+func (am *Alertmanager) breakerFailure(err error) {
+	am.lock.Lock()
+	defer am.lock.Unlock()
+
+	am.breaker.failures++
+	am.breaker.lastError = err.Error()
+	if am.BreakerFailures > 0 && am.breaker.failures >= am.BreakerFailures {
+		am.breaker.openedAt = time.Now()
+		am.Metrics.BreakerTrips++
+	}
+}
+
+// breakerSuccess records a successful pull and closes the circuit breaker
+func (am *Alertmanager) breakerSuccess() {
+	am.lock.Lock()
+	defer am.lock.Unlock()
+
+	am.breaker = circuitBreaker{}
+}
+
+// BreakerState returns the current state of the circuit breaker
+func (am *Alertmanager) BreakerState() string {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	return am.breaker.state(am.BreakerFailures, am.BreakerCooldown)
+}
+
+// ConsecutiveFailures returns the number of consecutive failed pulls
+func (am *Alertmanager) ConsecutiveFailures() int {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	return am.breaker.failures
+}
+
+// BreakerRetryAt returns the time when the circuit breaker will allow to
+// pull from this Alertmanager again, zero value if it's not open
+func (am *Alertmanager) BreakerRetryAt() time.Time {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	if am.breaker.state(am.BreakerFailures, am.BreakerCooldown) == BreakerClosed {
+		return time.Time{}
+	}
+	return am.breaker.openedAt.Add(am.BreakerCooldown)
+}
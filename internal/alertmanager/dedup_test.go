@@ -99,9 +99,12 @@ func TestDedupAutocomplete(t *testing.T) {
 	// 56 hints for everything except @alertmanager and @silence_id
 	// 4 hints for @silence_id 1 and 2
 	// 2 hints per @alertmanager
+	// 2 hints per @cluster, mocks aren't clustered so it's one per alertmanager
 	// 6 hints for silences in for each alertmanager
+	// 12 hints for @annotation.<name>
+	// 8 hints for @silence_expires and @silence_age
 	// silence id might get duplicated so this check isn't very strict
-	expected := 56 + 4 + mockCount*2 + mockCount*6
+	expected := 56 + 4 + mockCount*2 + mockCount*2 + mockCount*6 + 12 + 8
 	if len(ac) <= int(float64(expected)*0.8) || len(ac) > expected {
 		t.Errorf("Expected %d autocomplete hints, got %d", expected, len(ac))
 	}
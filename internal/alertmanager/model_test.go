@@ -2,6 +2,7 @@ package alertmanager
 
 import (
 	"testing"
+	"time"
 )
 
 type uriTest struct {
@@ -101,3 +102,74 @@ func TestAlertmanagerURI(t *testing.T) {
 		}
 	}
 }
+
+func TestAlertmanagerInterval(t *testing.T) {
+	am, err := NewAlertmanager("test", "http://localhost")
+	if err != nil {
+		t.Error(err)
+	}
+	if am.Interval != time.Minute {
+		t.Errorf("Default interval mismatch, expected '%v', got '%v'", time.Minute, am.Interval)
+	}
+
+	am, err = NewAlertmanager("test", "http://localhost", WithInterval(time.Second*15))
+	if err != nil {
+		t.Error(err)
+	}
+	if am.Interval != time.Second*15 {
+		t.Errorf("Interval mismatch, expected '%v', got '%v'", time.Second*15, am.Interval)
+	}
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		_, err = NewAlertmanager("test", "http://localhost", WithInterval(interval))
+		if err == nil {
+			t.Errorf("WithInterval(%v) didn't return any error", interval)
+		}
+	}
+}
+
+func TestUnregisterAlertmanager(t *testing.T) {
+	am, err := NewAlertmanager("unregister", "http://unregister.localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = RegisterAlertmanager(am); err != nil {
+		t.Fatal(err)
+	}
+	if GetAlertmanagerByName("unregister") == nil {
+		t.Error("GetAlertmanagerByName() returned nil for registered instance")
+	}
+
+	if err = UnregisterAlertmanager("unregister"); err != nil {
+		t.Error(err)
+	}
+	if GetAlertmanagerByName("unregister") != nil {
+		t.Error("GetAlertmanagerByName() returned removed instance")
+	}
+	if err = UnregisterAlertmanager("unregister"); err == nil {
+		t.Error("UnregisterAlertmanager() didn't return any error for unknown instance")
+	}
+
+	// instance can be registered again once removed
+	if err = RegisterAlertmanager(am); err != nil {
+		t.Error(err)
+	}
+	if err = UnregisterAlertmanager("unregister"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAlertFingerprint(t *testing.T) {
+	// fingerprint returned by Alertmanager 0.19.0 for this alert
+	labels := map[string]string{
+		"alertname": "Free_Disk_Space_Too_Low",
+		"cluster":   "staging",
+		"disk":      "sda",
+		"instance":  "server5",
+		"job":       "node_exporter",
+	}
+	expected := "aae7a1432b5d2f1b"
+	if fp := alertFingerprint(labels); fp != expected {
+		t.Errorf("alertFingerprint() returned '%s', expected '%s'", fp, expected)
+	}
+}
@@ -2,6 +2,7 @@ package alertmanager
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"path"
 	"sort"
@@ -18,17 +19,51 @@ import (
 	"github.com/prymitive/karma/internal/uri"
 	"github.com/prymitive/karma/internal/verprobe"
 
+	"github.com/Masterminds/semver/v3"
+	"github.com/cnf/structhash"
+	"github.com/prometheus/common/model"
+
 	log "github.com/sirupsen/logrus"
 )
 
 const (
 	labelValueErrorsAlerts   = "alerts"
 	labelValueErrorsSilences = "silences"
+
+	// fakeVersion is used when we fail to detect the version of Alertmanager
+	fakeVersion = "999.0.0"
+	// versionProbeTTL controls how long version probe results are cached for
+	versionProbeTTL = time.Minute * 15
+	// versionSourceConfig is the source reported for pinned versions
+	versionSourceConfig = "config"
+	// versionSourceStatus is the source reported for versions that were
+	// different from the probe result when we fetched Alertmanager status
+	versionSourceStatus = "status"
 )
 
+// versionProbes is the list of all methods used to detect Alertmanager
+// version, in the order they will be tried
+var versionProbes = []struct {
+	path   string
+	detect func(io.Reader) (string, error)
+}{
+	{path: "metrics", detect: verprobe.Detect},
+	{path: "api/v2/status", detect: verprobe.DetectFromStatus},
+	{path: "api/v1/status", detect: verprobe.DetectFromStatus},
+}
+
+type versionProbeResult struct {
+	version   string
+	source    string
+	err       string
+	timestamp time.Time
+}
+
 type alertmanagerMetrics struct {
-	Cycles float64
-	Errors map[string]float64
+	Cycles       float64
+	Errors       map[string]float64
+	Retries      float64
+	BreakerTrips float64
 }
 
 // Alertmanager represents Alertmanager upstream instance
@@ -36,7 +71,25 @@ type Alertmanager struct {
 	URI            string        `json:"uri"`
 	ExternalURI    string        `json:"-"`
 	RequestTimeout time.Duration `json:"timeout"`
-	Name           string        `json:"name"`
+	Interval       time.Duration `json:"interval"`
+	GracePeriod    time.Duration `json:"gracePeriod"`
+	// how many times failed pull is retried, first retry is delayed by
+	// RetryBackoff and each next one waits twice as long
+	RetryAttempts int           `json:"retryAttempts"`
+	RetryBackoff  time.Duration `json:"retryBackoff"`
+	// after BreakerFailures consecutive failed pulls we stop pulling from this
+	// instance for BreakerCooldown, 0 disables the circuit breaker
+	BreakerFailures int           `json:"breakerFailures"`
+	BreakerCooldown time.Duration `json:"breakerCooldown"`
+	Name            string        `json:"name"`
+	// Cluster is the optional name of the cluster this instance belongs to,
+	// if no member of the cluster sets it we use member names instead
+	Cluster string `json:"cluster"`
+	// alerts are only collected for receivers matching ReceiverRegex and with
+	// labels matching all Matchers, both are passed to the Alertmanager API
+	// so only Alertmanager versions using OpenAPI support those
+	ReceiverRegex string   `json:"receiverRegex"`
+	Matchers      []string `json:"matchers"`
 	// whenever this instance should be proxied
 	ProxyRequests bool `json:"proxyRequests"`
 	// reader instances are specific to URI scheme we collect from
@@ -54,34 +107,96 @@ type Alertmanager struct {
 	knownLabels  []string
 	lastError    string
 	status       models.AlertmanagerStatus
+	// lastPull is the timestamp of the last successful pull, stale is set if
+	// we failed to pull since then but we still keep old data
+	lastPull time.Time
+	stale    bool
+	// versionPin is the version set in the config, if set we will never
+	// try to detect it
+	versionPin   string
+	versionProbe versionProbeResult
+	breaker      circuitBreaker
 	// metrics tracked per alertmanager instance
 	Metrics alertmanagerMetrics
 	// headers to send with each AlertManager request
 	HTTPHeaders map[string]string
+	// stop is closed when this instance is removed, it will abort any pending
+	// retries
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
-func (am *Alertmanager) probeVersion() string {
-	const fakeVersion = "999.0.0"
-
-	url, err := uri.JoinURL(am.URI, "metrics")
+func (am *Alertmanager) probeVersionFrom(path string, detect func(io.Reader) (string, error)) (string, error) {
+	url, err := uri.JoinURL(am.URI, path)
 	if err != nil {
-		log.Errorf("Failed to join url '%s' and path 'metrics': %s", am.SanitizedURI(), err)
-		return fakeVersion
+		return "", err
 	}
 
 	source, err := am.reader.Read(url, am.HTTPHeaders)
 	if err != nil {
-		log.Errorf("[%s] %s request failed: %s", am.Name, uri.SanitizeURI(url), err)
-		return fakeVersion
+		return "", err
 	}
 	defer source.Close()
 
-	version, err := verprobe.Detect(source)
+	version, err := detect(source)
 	if err != nil {
-		return fakeVersion
+		return "", err
+	}
+
+	if _, err = semver.NewVersion(version); err != nil {
+		return "", fmt.Errorf("invalid version '%s': %s", version, err)
 	}
 
-	return version
+	return version, nil
+}
+
+// probeVersion returns the version of this Alertmanager and a flag telling
+// if it was read from the cache of a previous probe
+func (am *Alertmanager) probeVersion() (string, bool) {
+	if am.versionPin != "" {
+		return am.versionPin, false
+	}
+
+	am.lock.RLock()
+	probe := am.versionProbe
+	am.lock.RUnlock()
+	if probe.err == "" && probe.version != "" && time.Since(probe.timestamp) < versionProbeTTL {
+		return probe.version, true
+	}
+
+	probe = versionProbeResult{timestamp: time.Now()}
+	errs := []string{}
+	for _, vp := range versionProbes {
+		version, err := am.probeVersionFrom(vp.path, vp.detect)
+		if err != nil {
+			log.Debugf("[%s] Version probe using %s failed: %s", am.Name, vp.path, err)
+			errs = append(errs, fmt.Sprintf("%s: %s", vp.path, err))
+			continue
+		}
+		probe.version = version
+		probe.source = vp.path
+		break
+	}
+
+	if probe.version == "" {
+		probe.version = fakeVersion
+		probe.err = strings.Join(errs, "; ")
+		log.Errorf("[%s] Failed to detect Alertmanager version, assuming %s: %s", am.Name, fakeVersion, probe.err)
+	}
+
+	am.lock.Lock()
+	am.versionProbe = probe
+	am.lock.Unlock()
+
+	return probe.version, false
+}
+
+// resetVersionProbe will clear cached version probe result, so it will be
+// run again on the next pull
+func (am *Alertmanager) resetVersionProbe() {
+	am.lock.Lock()
+	am.versionProbe = versionProbeResult{}
+	am.lock.Unlock()
 }
 
 func (am *Alertmanager) fetchStatus(version string) (*models.AlertmanagerStatus, error) {
@@ -134,13 +249,90 @@ func (am *Alertmanager) clearData() {
 		ID:      "",
 		PeerIDs: []string{},
 	}
+	am.stale = false
+	am.lock.Unlock()
+}
+
+// CopyData will copy all data pulled by another instance, it's used when an
+// instance is replaced so it can serve old data until the first pull is done
+func (am *Alertmanager) CopyData(source *Alertmanager) {
+	source.lock.RLock()
+	alertGroups := source.alertGroups
+	silences := source.silences
+	colors := source.colors
+	autocomplete := source.autocomplete
+	knownLabels := source.knownLabels
+	lastError := source.lastError
+	status := source.status
+	lastPull := source.lastPull
+	stale := source.stale
+	source.lock.RUnlock()
+
+	am.lock.Lock()
+	am.alertGroups = alertGroups
+	am.silences = silences
+	am.colors = colors
+	am.autocomplete = autocomplete
+	am.knownLabels = knownLabels
+	am.lastError = lastError
+	am.status = status
+	am.lastPull = lastPull
+	am.stale = stale
 	am.lock.Unlock()
 }
 
-func (am *Alertmanager) pullSilences(version string) error {
+// markStale will flag all alerts collected from this instance as stale
+func (am *Alertmanager) markStale() {
+	am.lock.Lock()
+	defer am.lock.Unlock()
+
+	if am.stale {
+		return
+	}
+	am.stale = true
+
+	// we need to copy all alerts, slices are shared with other copies
+	staleGroups := make([]models.AlertGroup, 0, len(am.alertGroups))
+	for _, ag := range am.alertGroups {
+		alerts := make(models.AlertList, 0, len(ag.Alerts))
+		for _, alert := range ag.Alerts {
+			instances := make([]models.AlertmanagerInstance, 0, len(alert.Alertmanager))
+			for _, instance := range alert.Alertmanager {
+				instance.Stale = true
+				instance.LastPull = am.lastPull
+				instances = append(instances, instance)
+			}
+			alert.Alertmanager = instances
+			alert.UpdateFingerprints()
+			alerts = append(alerts, alert)
+		}
+		ag.Alerts = alerts
+		ag.Hash = ag.ContentFingerprint()
+		staleGroups = append(staleGroups, ag)
+	}
+	am.alertGroups = staleGroups
+}
+
+// pullFailed handles any error during Pull(), if we're still within grace
+// period then all data from the last successful pull is kept and marked as
+// stale, otherwise all data is cleared
+func (am *Alertmanager) pullFailed(err error) {
+	am.setError(err.Error())
+
+	lastPull := am.LastPull()
+	if am.GracePeriod > 0 && !lastPull.IsZero() && time.Since(lastPull) < am.GracePeriod {
+		log.Warningf("[%s] Pull failed, using stale data from last successful pull at %s", am.Name, lastPull)
+		am.markStale()
+		return
+	}
+
+	am.clearData()
+}
+
+func (am *Alertmanager) fetchSilences(version string) ([]models.Silence, error) {
 	mapper, err := mapper.GetSilenceMapper(version)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var silences []models.Silence
@@ -149,14 +341,14 @@ func (am *Alertmanager) pullSilences(version string) error {
 	if mapper.IsOpenAPI() {
 		silences, err = mapper.Collect(am.URI, am.HTTPHeaders, am.RequestTimeout, am.HTTPTransport)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		// generate full URL to collect silences from
 		url, err := mapper.AbsoluteURL(am.URI)
 		if err != nil {
 			log.Errorf("[%s] Failed to generate silences endpoint URL: %s", am.Name, err)
-			return err
+			return nil, err
 		}
 		// append query args if mapper needs those
 		queryArgs := mapper.QueryArgs()
@@ -168,18 +360,22 @@ func (am *Alertmanager) pullSilences(version string) error {
 		source, err := am.reader.Read(url, am.HTTPHeaders)
 		if err != nil {
 			log.Errorf("[%s] %s request failed: %s", am.Name, uri.SanitizeURI(url), err)
-			return err
+			return nil, err
 		}
 		defer source.Close()
 
 		// decode body text
 		silences, err = mapper.Decode(source)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	log.Infof("[%s] Got %d silences(s) in %s", am.Name, len(silences), time.Since(start))
 
+	return silences, nil
+}
+
+func (am *Alertmanager) processSilences(silences []models.Silence) map[string]models.Silence {
 	log.Infof("[%s] Detecting JIRA links in silences (%d)", am.Name, len(silences))
 	silenceMap := map[string]models.Silence{}
 	for _, silence := range silences {
@@ -187,12 +383,7 @@ func (am *Alertmanager) pullSilences(version string) error {
 		silence.JiraID, silence.JiraURL = transform.DetectJIRAs(&silence)
 		silenceMap[silence.ID] = silence
 	}
-
-	am.lock.Lock()
-	am.silences = silenceMap
-	am.lock.Unlock()
-
-	return nil
+	return silenceMap
 }
 
 // InternalURI is the URI of this Alertmanager that will be used for all request made by the UI
@@ -223,19 +414,28 @@ func (am *Alertmanager) PublicURI() string {
 	return am.URI
 }
 
-func (am *Alertmanager) pullAlerts(version string) error {
+// alertFilter returns filters that will be passed to the Alertmanager API
+// when collecting alerts
+func (am *Alertmanager) alertFilter() mapper.AlertFilter {
+	return mapper.AlertFilter{
+		Receiver: am.ReceiverRegex,
+		Matchers: am.Matchers,
+	}
+}
+
+func (am *Alertmanager) fetchAlerts(version string) ([]models.AlertGroup, error) {
 	mapper, err := mapper.GetAlertMapper(version)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var groups []models.AlertGroup
 
 	start := time.Now()
 	if mapper.IsOpenAPI() {
-		groups, err = mapper.Collect(am.URI, am.HTTPHeaders, am.RequestTimeout, am.HTTPTransport)
+		groups, err = mapper.Collect(am.URI, am.HTTPHeaders, am.RequestTimeout, am.HTTPTransport, am.alertFilter())
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 
@@ -243,7 +443,7 @@ func (am *Alertmanager) pullAlerts(version string) error {
 		url, err := mapper.AbsoluteURL(am.URI)
 		if err != nil {
 			log.Errorf("[%s] Failed to generate alerts endpoint URL: %s", am.Name, err)
-			return err
+			return nil, err
 		}
 
 		// append query args if mapper needs those
@@ -256,18 +456,35 @@ func (am *Alertmanager) pullAlerts(version string) error {
 		source, err := am.reader.Read(url, am.HTTPHeaders)
 		if err != nil {
 			log.Errorf("[%s] %s request failed: %s", am.Name, uri.SanitizeURI(url), err)
-			return err
+			return nil, err
 		}
 		defer source.Close()
 
 		// decode body text
 		groups, err = mapper.Decode(source)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	log.Infof("[%s] Got %d alert group(s) in %s", am.Name, len(groups), time.Since(start))
 
+	return groups, nil
+}
+
+// alertFingerprint returns the fingerprint Alertmanager would generate for
+// an alert with given labels
+func alertFingerprint(labels map[string]string) string {
+	ls := model.LabelSet{}
+	for k, v := range labels {
+		ls[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return ls.Fingerprint().String()
+}
+
+// processAlerts will deduplicate alert groups and link all alerts with
+// silences, it needs to run after both alerts and silences are fetched
+func (am *Alertmanager) processAlerts(groups []models.AlertGroup, silenceMap map[string]models.Silence) {
+
 	log.Infof("[%s] Deduplicating alert groups (%d)", am.Name, len(groups))
 	uniqueGroups := map[string]models.AlertGroup{}
 	uniqueAlerts := map[string]map[string]models.Alert{}
@@ -282,6 +499,9 @@ func (am *Alertmanager) pullAlerts(version string) error {
 			}
 		}
 		for _, alert := range ag.Alerts {
+			// compute the fingerprint before any label is stripped, it needs to
+			// match the value Alertmanager uses for inhibitedBy
+			alert.Fingerprint = alertFingerprint(alert.Labels)
 			if _, found := uniqueAlerts[agID]; !found {
 				uniqueAlerts[agID] = map[string]models.Alert{}
 			}
@@ -307,8 +527,8 @@ func (am *Alertmanager) pullAlerts(version string) error {
 
 			silences := map[string]*models.Silence{}
 			for _, silenceID := range alert.SilencedBy {
-				silence, err := am.SilenceByID(silenceID)
-				if err == nil {
+				if silence, found := silenceMap[silenceID]; found {
+					silence := silence // scopelint pin
 					silences[silenceID] = &silence
 				}
 			}
@@ -317,6 +537,7 @@ func (am *Alertmanager) pullAlerts(version string) error {
 				{
 					Name:        am.Name,
 					Cluster:     am.ClusterID(),
+					ClusterName: am.ClusterName(),
 					State:       alert.State,
 					StartsAt:    alert.StartsAt,
 					Source:      alert.GeneratorURL,
@@ -364,47 +585,180 @@ func (am *Alertmanager) pullAlerts(version string) error {
 
 	am.lock.Lock()
 	am.alertGroups = dedupedGroups
+	am.silences = silenceMap
 	am.colors = colors
 	am.autocomplete = autocomplete
 	am.knownLabels = knownLabels
 	am.lock.Unlock()
+}
 
-	return nil
+// fetchSilencesAndAlerts will fetch silences and alerts in parallel, if any
+// request fails it returns the error and the name of the endpoint it failed for
+func (am *Alertmanager) fetchSilencesAndAlerts(version string) ([]models.Silence, []models.AlertGroup, string, error) {
+	var silences []models.Silence
+	var groups []models.AlertGroup
+	var silencesErr, alertsErr error
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		silences, silencesErr = am.fetchSilences(version)
+	}()
+	go func() {
+		defer wg.Done()
+		groups, alertsErr = am.fetchAlerts(version)
+	}()
+	wg.Wait()
+
+	if silencesErr != nil {
+		return nil, nil, labelValueErrorsSilences, silencesErr
+	}
+	if alertsErr != nil {
+		return nil, nil, labelValueErrorsAlerts, alertsErr
+	}
+	return silences, groups, "", nil
+}
+
+// collect runs all requests needed to pull data from this Alertmanager, all
+// requests are sent in parallel, it returns the status and, if any request
+// fails, the error and the name of the endpoint it failed for
+func (am *Alertmanager) collect(version string) (*models.AlertmanagerStatus, string, error) {
+	var status *models.AlertmanagerStatus
+	var statusErr error
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		status, statusErr = am.fetchStatus(version)
+	}()
+	silences, groups, endpoint, err := am.fetchSilencesAndAlerts(version)
+	wg.Wait()
+
+	if statusErr != nil {
+		return nil, labelValueErrorsSilences, statusErr
+	}
+
+	if am.versionPin == "" && status.Version != "" && status.Version != version {
+		// Alertmanager was likely upgraded, trust the version it reports
+		if _, verr := semver.NewVersion(status.Version); verr == nil {
+			log.Infof("[%s] Alertmanager reported version %s, detected version was %s", am.Name, status.Version, version)
+			version = status.Version
+			am.lock.Lock()
+			am.versionProbe = versionProbeResult{
+				version:   version,
+				source:    versionSourceStatus,
+				timestamp: time.Now(),
+			}
+			am.lock.Unlock()
+			// silences and alerts were fetched for the old version, we need
+			// to fetch those again
+			silences, groups, endpoint, err = am.fetchSilencesAndAlerts(version)
+		}
+	}
+	if err != nil {
+		return nil, endpoint, err
+	}
+
+	// silences are linked to alerts, so we can only process alerts once we
+	// have both
+	am.processAlerts(groups, am.processSilences(silences))
+
+	return status, "", nil
+}
+
+// retryDelay returns how long we should wait before given retry attempt,
+// delay is doubled on every attempt but never exceeds the interval
+func (am *Alertmanager) retryDelay(attempt int) time.Duration {
+	delay := am.RetryBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if am.Interval > 0 && delay >= am.Interval {
+			return am.Interval
+		}
+	}
+	return delay
+}
+
+// Stop will abort any pending pull retries, it should be called once this
+// instance is no longer used
+func (am *Alertmanager) Stop() {
+	am.stopOnce.Do(func() {
+		close(am.stop)
+	})
 }
 
 // Pull data from upstream Alertmanager instance
 func (am *Alertmanager) Pull() error {
 	am.Metrics.Cycles++
 
-	version := am.probeVersion()
-
-	status, err := am.fetchStatus(version)
-	if err != nil {
-		am.clearData()
-		am.setError(err.Error())
-		am.Metrics.Errors[labelValueErrorsSilences]++
+	// no requests are sent while the breaker is open, but we still need to
+	// expire stale data once grace period passes
+	if err := am.breakerAllows(); err != nil {
+		am.pullFailed(err)
 		return err
 	}
 
-	err = am.pullSilences(version)
-	if err != nil {
-		am.clearData()
-		am.setError(err.Error())
-		am.Metrics.Errors[labelValueErrorsSilences]++
-		return err
+	// when the breaker is half-open we only send a single probe request
+	attempts := am.RetryAttempts
+	if am.BreakerState() == BreakerHalfOpen {
+		attempts = 0
+	}
+
+	var err error
+	for attempt := 0; attempt <= attempts; attempt++ {
+		if attempt > 0 {
+			delay := am.retryDelay(attempt)
+			log.Warningf("[%s] Pull failed, retrying in %s (%d/%d): %s", am.Name, delay, attempt, attempts, err)
+			am.Metrics.Retries++
+			select {
+			case <-time.After(delay):
+			case <-am.stop:
+				log.Warningf("[%s] Alertmanager was removed, aborting pull retries", am.Name)
+				return err
+			}
+		}
+		if err = am.pull(); err == nil {
+			am.breakerSuccess()
+			return nil
+		}
 	}
 
-	err = am.pullAlerts(version)
+	am.pullFailed(err)
+	am.breakerFailure(err)
+	if am.BreakerState() == BreakerOpen {
+		log.Errorf("[%s] Circuit breaker is open after %d consecutive failure(s), skipping pulls for %s",
+			am.Name, am.ConsecutiveFailures(), am.BreakerCooldown)
+	}
+	return err
+}
+
+// pull runs a single attempt to collect all data from upstream
+func (am *Alertmanager) pull() error {
+	version, cached := am.probeVersion()
+
+	status, endpoint, err := am.collect(version)
+	if err != nil && cached {
+		// Alertmanager might have been upgraded since we last detected its
+		// version, re-run detection and try again if the version changed
+		am.resetVersionProbe()
+		if newVersion, _ := am.probeVersion(); newVersion != version {
+			log.Infof("[%s] Detected version changed from %s to %s", am.Name, version, newVersion)
+			version = newVersion
+			status, endpoint, err = am.collect(version)
+		}
+	}
 	if err != nil {
-		am.clearData()
-		am.setError(err.Error())
-		am.Metrics.Errors[labelValueErrorsAlerts]++
+		am.Metrics.Errors[endpoint]++
 		return err
 	}
 
 	am.lock.Lock()
 	am.status = *status
 	am.lastError = ""
+	am.lastPull = time.Now()
+	am.stale = false
 	am.lock.Unlock()
 
 	return nil
@@ -420,6 +774,39 @@ func (am *Alertmanager) Alerts() []models.AlertGroup {
 	return alerts
 }
 
+// Fingerprint returns a checksum of all data pulled from this instance, it
+// will only change if alerts, silences or upstream state was modified, pull
+// timestamps are not included
+func (am *Alertmanager) Fingerprint() string {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	keys := []string{}
+	for _, ag := range am.alertGroups {
+		keys = append(keys, fmt.Sprintf("%s:%s", ag.ID, ag.Hash))
+	}
+	for id, silence := range am.silences {
+		keys = append(keys, fmt.Sprintf("%s:%x", id, structhash.Sha1(silence, 1)))
+	}
+	sort.Strings(keys)
+	keys = append(keys,
+		fmt.Sprintf("%x", structhash.Sha1(am.status, 1)),
+		am.lastError,
+		fmt.Sprintf("%v", am.stale),
+		am.versionProbe.version,
+		am.versionProbe.source,
+		am.versionProbe.err,
+		am.breaker.state(am.BreakerFailures, am.BreakerCooldown),
+		fmt.Sprintf("%d", am.breaker.failures),
+	)
+
+	fp, err := slices.StringSliceToSHA1(keys)
+	if err != nil {
+		log.Errorf("[%s] Failed to compute fingerprint: %s", am.Name, err)
+	}
+	return fp
+}
+
 // Silences returns a copy of all silences
 func (am *Alertmanager) Silences() map[string]models.Silence {
 	am.lock.RLock()
@@ -494,6 +881,47 @@ func (am *Alertmanager) Error() string {
 	return am.lastError
 }
 
+// IsStale returns true if last pull failed and we're using data from the
+// last successful pull
+func (am *Alertmanager) IsStale() bool {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	return am.stale
+}
+
+// LastPull returns the timestamp of the last successful pull
+func (am *Alertmanager) LastPull() time.Time {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	return am.lastPull
+}
+
+// VersionSource returns the source of the last detected version, this is
+// either the path of the API endpoint used to detect it, "status" if the
+// version was updated from the status response or "config" if it was pinned
+// in the config file
+func (am *Alertmanager) VersionSource() string {
+	if am.versionPin != "" {
+		return versionSourceConfig
+	}
+
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	return am.versionProbe.source
+}
+
+// VersionProbeError returns the error from the last version probe, empty if
+// the version was detected
+func (am *Alertmanager) VersionProbeError() string {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
+	return am.versionProbe.err
+}
+
 // SanitizedURI returns a copy of Alertmanager.URI with password replaced by
 // "xxx"
 func (am *Alertmanager) SanitizedURI() string {
@@ -545,6 +973,23 @@ func (am *Alertmanager) ClusterMemberNames() []string {
 	return members
 }
 
+// ClusterName returns the human friendly name of the cluster this Alertmanager
+// instance belongs to, it's the first cluster name set on any cluster member
+// or, if none is set, a comma separated list of all member names
+func (am *Alertmanager) ClusterName() string {
+	members := am.ClusterMemberNames()
+	for _, member := range members {
+		upstream := am
+		if member != am.Name {
+			upstream = GetAlertmanagerByName(member)
+		}
+		if upstream != nil && upstream.Cluster != "" {
+			return upstream.Cluster
+		}
+	}
+	return strings.Join(members, ",")
+}
+
 // ClusterID returns the ID (sha1) of the cluster this Alertmanager instance
 // belongs to
 func (am *Alertmanager) ClusterID() string {
@@ -0,0 +1,464 @@
+package alertmanager_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+
+	"github.com/prymitive/karma/internal/alertmanager"
+	"github.com/prymitive/karma/internal/mock"
+)
+
+func mockUpstream(uri, version string) {
+	mock.RegisterURL(fmt.Sprintf("%s/metrics", uri), version, "metrics")
+	mock.RegisterURL(fmt.Sprintf("%s/api/v2/status", uri), version, "api/v2/status")
+	mock.RegisterURL(fmt.Sprintf("%s/api/v2/silences", uri), version, "api/v2/silences")
+	mock.RegisterURL(fmt.Sprintf("%s/api/v2/alerts/groups", uri), version, "api/v2/alerts/groups")
+}
+
+func failUpstream(uri string) {
+	for _, path := range []string{"api/v2/status", "api/v2/silences", "api/v2/alerts/groups"} {
+		httpmock.RegisterResponder("GET", fmt.Sprintf("%s/%s", uri, path), httpmock.NewStringResponder(500, "error"))
+	}
+}
+
+type gracePeriodTest struct {
+	name        string
+	gracePeriod time.Duration
+	stale       bool
+}
+
+var gracePeriodTests = []gracePeriodTest{
+	{
+		name:        "no-grace",
+		gracePeriod: 0,
+		stale:       false,
+	},
+	{
+		name:        "grace",
+		gracePeriod: time.Hour,
+		stale:       true,
+	},
+}
+
+func TestPullGracePeriod(t *testing.T) {
+	for _, testCase := range gracePeriodTests {
+		uri := fmt.Sprintf("http://%s.localhost", testCase.name)
+		mockUpstream(uri, "0.19.0")
+
+		am, err := alertmanager.NewAlertmanager(testCase.name, uri, alertmanager.WithGracePeriod(testCase.gracePeriod))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = am.Pull(); err != nil {
+			t.Fatalf("[%s] Pull() failed: %s", testCase.name, err)
+		}
+		groups := am.Alerts()
+		if len(groups) == 0 {
+			t.Fatalf("[%s] No alert groups after Pull()", testCase.name)
+		}
+		lastPull := am.LastPull()
+		if lastPull.IsZero() {
+			t.Errorf("[%s] LastPull() is not set after successful pull", testCase.name)
+		}
+
+		failUpstream(uri)
+		if err = am.Pull(); err == nil {
+			t.Fatalf("[%s] Pull() didn't return any error", testCase.name)
+		}
+		if am.Error() == "" {
+			t.Errorf("[%s] Error() is empty after failed pull", testCase.name)
+		}
+		if am.IsStale() != testCase.stale {
+			t.Errorf("[%s] IsStale() returned %v, expected %v", testCase.name, am.IsStale(), testCase.stale)
+		}
+		if am.LastPull() != lastPull {
+			t.Errorf("[%s] LastPull() changed after failed pull", testCase.name)
+		}
+
+		staleGroups := am.Alerts()
+		if !testCase.stale {
+			if len(staleGroups) != 0 {
+				t.Errorf("[%s] Got %d alert groups after failed pull, expected 0", testCase.name, len(staleGroups))
+			}
+			continue
+		}
+		if len(staleGroups) != len(groups) {
+			t.Errorf("[%s] Got %d alert groups after failed pull, expected %d", testCase.name, len(staleGroups), len(groups))
+		}
+		for _, ag := range staleGroups {
+			for _, alert := range ag.Alerts {
+				for _, instance := range alert.Alertmanager {
+					if !instance.Stale {
+						t.Errorf("[%s] Alertmanager instance not marked as stale: %v", testCase.name, instance)
+					}
+					if instance.LastPull != lastPull {
+						t.Errorf("[%s] Alertmanager instance LastPull is %s, expected %s", testCase.name, instance.LastPull, lastPull)
+					}
+				}
+			}
+		}
+		// original copies must not be modified
+		for _, ag := range groups {
+			for _, alert := range ag.Alerts {
+				for _, instance := range alert.Alertmanager {
+					if instance.Stale {
+						t.Errorf("[%s] Alert copy returned before failed pull was modified: %v", testCase.name, instance)
+					}
+				}
+			}
+		}
+
+		mockUpstream(uri, "0.19.0")
+		if err = am.Pull(); err != nil {
+			t.Fatalf("[%s] Pull() failed: %s", testCase.name, err)
+		}
+		if am.IsStale() {
+			t.Errorf("[%s] IsStale() returned true after successful pull", testCase.name)
+		}
+	}
+}
+
+type versionProbeTest struct {
+	name    string
+	version string
+	paths   []string
+	pin     string
+	source  string
+	isError bool
+}
+
+var versionProbeTests = []versionProbeTest{
+	{
+		name:    "probe-metrics",
+		version: "0.19.0",
+		paths:   []string{"metrics", "api/v2/status"},
+		source:  "metrics",
+	},
+	{
+		name:    "probe-v2",
+		version: "0.19.0",
+		paths:   []string{"api/v2/status"},
+		source:  "api/v2/status",
+	},
+	{
+		name:    "probe-v1",
+		version: "0.15.3",
+		paths:   []string{"api/v1/status"},
+		source:  "api/v1/status",
+	},
+	{
+		name:    "probe-pinned",
+		version: "0.19.0",
+		paths:   []string{"api/v2/status"},
+		pin:     "0.19.0",
+		source:  "config",
+	},
+	{
+		name:    "probe-failed",
+		version: "0.19.0",
+		paths:   []string{},
+		source:  "",
+		isError: true,
+	},
+}
+
+func TestVersionProbe(t *testing.T) {
+	for _, testCase := range versionProbeTests {
+		uri := fmt.Sprintf("http://%s.localhost", testCase.name)
+		for _, path := range testCase.paths {
+			mock.RegisterURL(fmt.Sprintf("%s/%s", uri, path), testCase.version, path)
+		}
+
+		am, err := alertmanager.NewAlertmanager(testCase.name, uri, alertmanager.WithVersion(testCase.pin))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// we only care about version detection here
+		_ = am.Pull()
+
+		if am.VersionSource() != testCase.source {
+			t.Errorf("[%s] VersionSource() returned %q, expected %q", testCase.name, am.VersionSource(), testCase.source)
+		}
+		if (am.VersionProbeError() != "") != testCase.isError {
+			t.Errorf("[%s] VersionProbeError() returned %q, expected error=%v", testCase.name, am.VersionProbeError(), testCase.isError)
+		}
+	}
+}
+
+func TestInvalidVersionPin(t *testing.T) {
+	_, err := alertmanager.NewAlertmanager("invalid", "http://localhost", alertmanager.WithVersion("foo"))
+	if err == nil {
+		t.Error("WithVersion(\"foo\") didn't return any error")
+	}
+}
+
+func TestPullRetry(t *testing.T) {
+	uri := "http://retry.localhost"
+	mockUpstream(uri, "0.19.0")
+	failUpstream(uri)
+
+	am, err := alertmanager.NewAlertmanager("retry", uri, alertmanager.WithRetry(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = am.Pull(); err == nil {
+		t.Fatal("Pull() didn't return any error")
+	}
+	if am.Metrics.Retries != 2 {
+		t.Errorf("Got %v retries, expected 2", am.Metrics.Retries)
+	}
+	if am.ConsecutiveFailures() != 1 {
+		t.Errorf("ConsecutiveFailures() returned %d, expected 1", am.ConsecutiveFailures())
+	}
+	if am.BreakerState() != alertmanager.BreakerClosed {
+		t.Errorf("BreakerState() returned %q with circuit breaker disabled", am.BreakerState())
+	}
+}
+
+func TestPullRetryStop(t *testing.T) {
+	uri := "http://retry-stop.localhost"
+	mockUpstream(uri, "0.19.0")
+	failUpstream(uri)
+
+	am, err := alertmanager.NewAlertmanager("retry-stop", uri, alertmanager.WithRetry(2, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- am.Pull()
+	}()
+
+	// Stop() can be called multiple times
+	am.Stop()
+	am.Stop()
+
+	select {
+	case err = <-done:
+		if err == nil {
+			t.Error("Pull() didn't return any error")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Pull() didn't return after Stop()")
+	}
+	if am.Metrics.Retries != 1 {
+		t.Errorf("Got %v retries, expected 1", am.Metrics.Retries)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	uri := "http://breaker.localhost"
+	mockUpstream(uri, "0.19.0")
+	failUpstream(uri)
+
+	am, err := alertmanager.NewAlertmanager("breaker", uri, alertmanager.WithCircuitBreaker(2, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		if am.BreakerState() != alertmanager.BreakerClosed {
+			t.Errorf("BreakerState() returned %q after %d failure(s)", am.BreakerState(), i-1)
+		}
+		if err = am.Pull(); err == nil {
+			t.Fatal("Pull() didn't return any error")
+		}
+	}
+	if am.BreakerState() != alertmanager.BreakerOpen {
+		t.Errorf("BreakerState() returned %q, expected %q", am.BreakerState(), alertmanager.BreakerOpen)
+	}
+	if am.BreakerRetryAt().IsZero() {
+		t.Error("BreakerRetryAt() is zero with open circuit breaker")
+	}
+	if am.Metrics.BreakerTrips != 1 {
+		t.Errorf("Got %v circuit breaker trips, expected 1", am.Metrics.BreakerTrips)
+	}
+
+	// no requests should be made while the breaker is open
+	mockUpstream(uri, "0.19.0")
+	calls := httpmock.GetTotalCallCount()
+	if err = am.Pull(); err == nil {
+		t.Error("Pull() didn't return any error with open circuit breaker")
+	}
+	if httpmock.GetTotalCallCount() != calls {
+		t.Errorf("Pull() sent %d request(s) with open circuit breaker", httpmock.GetTotalCallCount()-calls)
+	}
+	if len(am.Alerts()) != 0 {
+		t.Errorf("Got %d alert groups with open circuit breaker", len(am.Alerts()))
+	}
+}
+
+func TestInvalidRetryAndBreakerOptions(t *testing.T) {
+	for _, opt := range []alertmanager.Option{
+		alertmanager.WithRetry(-1, time.Second),
+		alertmanager.WithRetry(1, 0),
+		alertmanager.WithCircuitBreaker(-1, time.Second),
+		alertmanager.WithCircuitBreaker(1, 0),
+	} {
+		if _, err := alertmanager.NewAlertmanager("invalid", "http://localhost", opt); err == nil {
+			t.Error("NewAlertmanager() didn't return any error for invalid option")
+		}
+	}
+}
+
+func TestPullLinksSilences(t *testing.T) {
+	uri := "http://silences.localhost"
+	mockUpstream(uri, "0.19.0")
+
+	am, err := alertmanager.NewAlertmanager("silences", uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = am.Pull(); err != nil {
+		t.Fatal(err)
+	}
+
+	silenced := 0
+	for _, ag := range am.Alerts() {
+		for _, alert := range ag.Alerts {
+			for _, instance := range alert.Alertmanager {
+				for _, silenceID := range instance.SilencedBy {
+					silenced++
+					if _, found := instance.Silences[silenceID]; !found {
+						t.Errorf("Silence '%s' not linked to alert %v", silenceID, alert.Labels)
+					}
+				}
+			}
+		}
+	}
+	if silenced == 0 {
+		t.Error("No silenced alerts found")
+	}
+}
+
+func TestPullWithAlertFilter(t *testing.T) {
+	uri := "http://filter.localhost"
+	mockUpstream(uri, "0.19.0")
+
+	var query url.Values
+	responder := httpmock.NewStringResponder(200, "[]")
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v2/alerts/groups", uri), func(req *http.Request) (*http.Response, error) {
+		query = req.URL.Query()
+		return responder(req)
+	})
+
+	am, err := alertmanager.NewAlertmanager(
+		"filter",
+		uri,
+		alertmanager.WithReceivers([]string{"by-name", "by.cluster"}),
+		alertmanager.WithMatchers([]string{`cluster="prod"`, `job=~"node.+"`}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = am.Pull(); err != nil {
+		t.Fatal(err)
+	}
+
+	if receiver := query.Get("receiver"); receiver != `^(?:by-name|by\.cluster)$` {
+		t.Errorf("Got receiver query '%s'", receiver)
+	}
+	if filter := query["filter"]; !reflect.DeepEqual(filter, []string{`cluster="prod"`, `job=~"node.+"`}) {
+		t.Errorf("Got filter query %v", filter)
+	}
+}
+
+func TestInvalidMatchers(t *testing.T) {
+	for _, matcher := range []string{"", "foo", "=bar", `foo="bar`, "1foo=bar"} {
+		if _, err := alertmanager.NewAlertmanager("invalid", "http://localhost", alertmanager.WithMatchers([]string{matcher})); err == nil {
+			t.Errorf("NewAlertmanager() didn't return any error for invalid matcher '%s'", matcher)
+		}
+	}
+}
+
+func TestClusterName(t *testing.T) {
+	mockUpstream("http://ha1.localhost", "0.19.0")
+	mockUpstream("http://ha2.localhost", "0.19.0")
+
+	ha1, err := alertmanager.NewAlertmanager("ha1", "http://ha1.localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ha2, err := alertmanager.NewAlertmanager("ha2", "http://ha2.localhost", alertmanager.WithCluster("prod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, am := range []*alertmanager.Alertmanager{ha1, ha2} {
+		if err = am.Pull(); err != nil {
+			t.Fatalf("[%s] Pull() failed: %s", am.Name, err)
+		}
+	}
+
+	// ha2 isn't registered yet, so ha1 doesn't know about the cluster name
+	expected := strings.Join(ha1.ClusterMemberNames(), ",")
+	if name := ha1.ClusterName(); name != expected {
+		t.Errorf("ClusterName() returned '%s' before ha2 was registered, expected '%s'", name, expected)
+	}
+
+	for _, am := range []*alertmanager.Alertmanager{ha1, ha2} {
+		if err = alertmanager.RegisterAlertmanager(am); err != nil {
+			t.Fatal(err)
+		}
+		defer func(name string) {
+			_ = alertmanager.UnregisterAlertmanager(name)
+		}(am.Name)
+	}
+
+	for _, am := range []*alertmanager.Alertmanager{ha1, ha2} {
+		if name := am.ClusterName(); name != "prod" {
+			t.Errorf("[%s] ClusterName() returned '%s', expected 'prod'", am.Name, name)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	uri := "http://fingerprint.localhost"
+	mockUpstream(uri, "0.19.0")
+
+	am, err := alertmanager.NewAlertmanager("fingerprint", uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty := am.Fingerprint()
+
+	if err = am.Pull(); err != nil {
+		t.Fatalf("Pull() failed: %s", err)
+	}
+	if am.Fingerprint() == empty {
+		t.Errorf("Fingerprint() didn't change after first pull")
+	}
+
+	// cluster ID of each alert is only known once status is pulled, so data
+	// might still change with the second pull
+	if err = am.Pull(); err != nil {
+		t.Fatalf("Pull() failed: %s", err)
+	}
+	fp := am.Fingerprint()
+
+	time.Sleep(time.Millisecond * 10)
+	if err = am.Pull(); err != nil {
+		t.Fatalf("Pull() failed: %s", err)
+	}
+	if am.Fingerprint() != fp {
+		t.Errorf("Fingerprint() changed after pulling the same data")
+	}
+
+	failUpstream(uri)
+	if err = am.Pull(); err == nil {
+		t.Fatal("Pull() didn't return any error")
+	}
+	if am.Fingerprint() == fp {
+		t.Errorf("Fingerprint() didn't change after failed pull")
+	}
+}
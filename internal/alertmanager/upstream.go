@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/prymitive/karma/internal/models"
 	"github.com/prymitive/karma/internal/uri"
 
+	"github.com/Masterminds/semver/v3"
+
 	log "github.com/sirupsen/logrus"
 )
 
@@ -19,22 +23,33 @@ type Option func(am *Alertmanager) error
 
 var (
 	upstreams = map[string]*Alertmanager{}
+	// upstreamsLock protects upstreams map, upstreams can be added or removed
+	// when configuration is reloaded
+	upstreamsLock = sync.RWMutex{}
+	// matcherRe is used to validate label matchers passed to WithMatchers,
+	// it follows the syntax accepted by the Alertmanager API: foo="bar",
+	// foo!="bar", foo=~"regex" and foo!~"regex", quotes are optional
+	matcherRe = regexp.MustCompile(`^\s*[a-zA-Z_][a-zA-Z0-9_]*\s*(=~|!~|!=|=)\s*("(\\.|[^"\\])*"|[^"]*)\s*$`)
 )
 
 // NewAlertmanager creates a new Alertmanager instance
 func NewAlertmanager(name, upstreamURI string, opts ...Option) (*Alertmanager, error) {
 	am := &Alertmanager{
-		URI:            upstreamURI,
-		ExternalURI:    "",
-		RequestTimeout: time.Second * 10,
-		Name:           name,
-		lock:           sync.RWMutex{},
-		alertGroups:    []models.AlertGroup{},
-		silences:       map[string]models.Silence{},
-		colors:         models.LabelsColorMap{},
-		autocomplete:   []models.Autocomplete{},
-		knownLabels:    []string{},
-		HTTPHeaders:    map[string]string{},
+		URI:             upstreamURI,
+		ExternalURI:     "",
+		RequestTimeout:  time.Second * 10,
+		Interval:        time.Minute,
+		RetryBackoff:    time.Second,
+		BreakerCooldown: time.Minute * 5,
+		Name:            name,
+		lock:            sync.RWMutex{},
+		alertGroups:     []models.AlertGroup{},
+		silences:        map[string]models.Silence{},
+		colors:          models.LabelsColorMap{},
+		autocomplete:    []models.Autocomplete{},
+		knownLabels:     []string{},
+		HTTPHeaders:     map[string]string{},
+		stop:            make(chan struct{}),
 		Metrics: alertmanagerMetrics{
 			Errors: map[string]float64{
 				labelValueErrorsAlerts:   0,
@@ -63,6 +78,9 @@ func NewAlertmanager(name, upstreamURI string, opts ...Option) (*Alertmanager, e
 // RegisterAlertmanager will add an Alertmanager instance to the list of
 // instances used when pulling alerts from upstreams
 func RegisterAlertmanager(am *Alertmanager) error {
+	upstreamsLock.Lock()
+	defer upstreamsLock.Unlock()
+
 	if _, found := upstreams[am.Name]; found {
 		return fmt.Errorf("alertmanager upstream '%s' already exist", am.Name)
 	}
@@ -77,8 +95,27 @@ func RegisterAlertmanager(am *Alertmanager) error {
 	return nil
 }
 
+// UnregisterAlertmanager will remove an Alertmanager instance from the list of
+// instances used when pulling alerts from upstreams
+func UnregisterAlertmanager(name string) error {
+	upstreamsLock.Lock()
+	defer upstreamsLock.Unlock()
+
+	am, found := upstreams[name]
+	if !found {
+		return fmt.Errorf("alertmanager upstream '%s' doesn't exist", name)
+	}
+	delete(upstreams, name)
+	am.Stop()
+	log.Infof("[%s] Removed Alertmanager source at %s", am.Name, am.SanitizedURI())
+	return nil
+}
+
 // GetAlertmanagers returns a list of all defined Alertmanager instances
 func GetAlertmanagers() []*Alertmanager {
+	upstreamsLock.RLock()
+	defer upstreamsLock.RUnlock()
+
 	ams := []*Alertmanager{}
 	for _, am := range upstreams {
 		ams = append(ams, am)
@@ -92,6 +129,9 @@ func GetAlertmanagers() []*Alertmanager {
 // GetAlertmanagerByName returns an instance of Alertmanager by name or nil
 // if not found
 func GetAlertmanagerByName(name string) *Alertmanager {
+	upstreamsLock.RLock()
+	defer upstreamsLock.RUnlock()
+
 	am, found := upstreams[name]
 	if found {
 		return am
@@ -108,6 +148,15 @@ func WithProxy(proxied bool) Option {
 	}
 }
 
+// WithCluster option can be passed to NewAlertmanager in order to set the
+// name of the cluster this instance belongs to
+func WithCluster(name string) Option {
+	return func(am *Alertmanager) error {
+		am.Cluster = name
+		return nil
+	}
+}
+
 // WithRequestTimeout option can be passed to NewAlertmanager in order to set
 // a custom timeout for Alertmanager upstream requests
 func WithRequestTimeout(timeout time.Duration) Option {
@@ -117,6 +166,117 @@ func WithRequestTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithInterval option can be passed to NewAlertmanager in order to set
+// a custom interval between each collection cycle for this instance
+func WithInterval(interval time.Duration) Option {
+	return func(am *Alertmanager) error {
+		if interval <= 0 {
+			return fmt.Errorf("invalid interval value '%v'", interval)
+		}
+		am.Interval = interval
+		return nil
+	}
+}
+
+// WithGracePeriod option can be passed to NewAlertmanager in order to keep
+// last known alerts and silences for this long after a failed pull, data kept
+// this way will be marked as stale
+func WithGracePeriod(gracePeriod time.Duration) Option {
+	return func(am *Alertmanager) error {
+		if gracePeriod < 0 {
+			return fmt.Errorf("invalid grace period value '%v'", gracePeriod)
+		}
+		am.GracePeriod = gracePeriod
+		return nil
+	}
+}
+
+// WithRetry option can be passed to NewAlertmanager in order to retry failed
+// pulls, each retry will wait twice as long as the previous one, starting
+// with the backoff value
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(am *Alertmanager) error {
+		if attempts < 0 {
+			return fmt.Errorf("invalid retry attempts value '%d'", attempts)
+		}
+		if attempts > 0 && backoff <= 0 {
+			return fmt.Errorf("invalid retry backoff value '%v'", backoff)
+		}
+		am.RetryAttempts = attempts
+		if backoff > 0 {
+			am.RetryBackoff = backoff
+		}
+		return nil
+	}
+}
+
+// WithReceivers option can be passed to NewAlertmanager in order to only
+// collect alerts for given list of receivers, the list is passed to the
+// Alertmanager API as a regex, empty list means all receivers
+func WithReceivers(receivers []string) Option {
+	return func(am *Alertmanager) error {
+		if len(receivers) == 0 {
+			am.ReceiverRegex = ""
+			return nil
+		}
+		quoted := make([]string, 0, len(receivers))
+		for _, receiver := range receivers {
+			quoted = append(quoted, regexp.QuoteMeta(receiver))
+		}
+		am.ReceiverRegex = fmt.Sprintf("^(?:%s)$", strings.Join(quoted, "|"))
+		return nil
+	}
+}
+
+// WithMatchers option can be passed to NewAlertmanager in order to only
+// collect alerts with labels matching all passed matchers, matchers are
+// passed to the Alertmanager API
+func WithMatchers(matchers []string) Option {
+	return func(am *Alertmanager) error {
+		for _, m := range matchers {
+			if !matcherRe.MatchString(m) {
+				return fmt.Errorf("invalid label matcher '%s'", m)
+			}
+		}
+		am.Matchers = matchers
+		return nil
+	}
+}
+
+// WithCircuitBreaker option can be passed to NewAlertmanager in order to stop
+// pulling from this instance for the cool-down period after it failed given
+// number of times in a row, 0 failures disables it
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(am *Alertmanager) error {
+		if failures < 0 {
+			return fmt.Errorf("invalid circuit breaker failures value '%d'", failures)
+		}
+		if failures > 0 && cooldown <= 0 {
+			return fmt.Errorf("invalid circuit breaker cooldown value '%v'", cooldown)
+		}
+		am.BreakerFailures = failures
+		if cooldown > 0 {
+			am.BreakerCooldown = cooldown
+		}
+		return nil
+	}
+}
+
+// WithVersion option can be passed to NewAlertmanager in order to pin the
+// version of this Alertmanager instance, it disables version detection
+func WithVersion(version string) Option {
+	return func(am *Alertmanager) error {
+		if version == "" {
+			return nil
+		}
+		if _, err := semver.NewVersion(version); err != nil {
+			return fmt.Errorf("invalid version '%s': %s", version, err)
+		}
+		am.versionPin = version
+		return nil
+	}
+}
+
 // WithHTTPHeaders option can be passed to NewAlertManager in order to set
 // a map of headers that will be passed with every request
 func WithHTTPHeaders(headers map[string]string) Option {
@@ -3,10 +3,12 @@ package config
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prymitive/karma/internal/slices"
@@ -21,6 +23,15 @@ import (
 var (
 	// Config will hold final configuration read from the file and flags
 	Config configSchema
+
+	// lock protects all keys that can be updated by Reload()
+	lock = sync.RWMutex{}
+	// configFileUsed is the path of the config file we've read
+	configFileUsed string
+
+	// GroupBySpecialLabels is the list of special label names that can be
+	// used to regroup alerts, other than regular alert labels
+	GroupBySpecialLabels = []string{"@alertmanager", "@cluster", "@receiver"}
 )
 
 func init() {
@@ -34,6 +45,16 @@ func init() {
 		"Alertmanager server URI used for web UI links (only used with simplified config)")
 	pflag.Duration("alertmanager.timeout", time.Second*40,
 		"Timeout for requests sent to the Alertmanager server (only used with simplified config)")
+	pflag.Duration("alertmanager.grace_period", 0,
+		"How long to keep showing last known data from the Alertmanager server after a failed request, 0 disables it")
+	pflag.Int("alertmanager.retry.attempts", 0,
+		"How many times to retry failed requests to the Alertmanager server, 0 disables retries")
+	pflag.Duration("alertmanager.retry.backoff", time.Second,
+		"How long to wait before the first retry, each next retry waits twice as long")
+	pflag.Int("alertmanager.circuit_breaker.failures", 0,
+		"Number of consecutive failed pulls after which the Alertmanager server is skipped, 0 disables it")
+	pflag.Duration("alertmanager.circuit_breaker.cooldown", time.Minute*5,
+		"How long to skip the Alertmanager server for after the circuit breaker opens")
 	pflag.Bool("alertmanager.proxy", false,
 		"Proxy all client requests to Alertmanager via karma (only used with simplified config)")
 
@@ -67,10 +88,15 @@ func init() {
 		"List of labels to keep, all other labels will be stripped")
 	pflag.StringSlice("labels.strip", []string{}, "List of labels to ignore")
 
+	pflag.StringSlice("grid.groupBy", []string{},
+		"List of label names to regroup alerts by, alerts are grouped the same way as in Alertmanager if empty")
 	pflag.String("grid.sorting.order", "startsAt", "Default sort order for alert grid")
 	pflag.Bool("grid.sorting.reverse", true, "Reverse sort order")
 	pflag.String("grid.sorting.label", "alertname", "Label name to use when sorting alert grid by label")
 
+	pflag.Int("health.min_healthy_upstreams", 0,
+		"Minimum number of healthy Alertmanager upstreams required for karma to report as ready, 0 disables it")
+
 	pflag.Bool("log.config", true, "Log used configuration to log on startup")
 	pflag.String("log.level", "info",
 		"Log level, one of: debug, info, warning, error, fatal and panic")
@@ -101,9 +127,63 @@ func init() {
 	pflag.String("ui.collapseGroups", "collapsedOnMobile", "Default state for alert groups")
 }
 
-// ReadConfig will read all sources of configuration, merge all keys and
+// Read will read all sources of configuration, merge all keys and
 // populate global Config variable, it should be only called on startup
 func (config *configSchema) Read() {
+	err := config.load()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Reload will read all sources of configuration again and update keys that
+// can be modified at runtime: alertmanager servers, JIRA rules and label
+// colors and views. Config is only updated if the new configuration is valid
+func (config *configSchema) Reload() error {
+	newConfig := configSchema{}
+	err := newConfig.load()
+	if err != nil {
+		return err
+	}
+
+	lock.Lock()
+	config.Alertmanager = newConfig.Alertmanager
+	config.JIRA = newConfig.JIRA
+	config.Labels.Color = newConfig.Labels.Color
+	config.Views = newConfig.Views
+	lock.Unlock()
+
+	return nil
+}
+
+// LabelColors returns label color rules, it's safe to call it while the
+// configuration is being reloaded
+func (config *configSchema) LabelColors() LabelColorRules {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return config.Labels.Color
+}
+
+// NamedViews returns the list of all configured views, it's safe to call it
+// while the configuration is being reloaded
+func (config *configSchema) NamedViews() []ViewConfig {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return config.Views
+}
+
+// File returns the path of the configuration file used during last read,
+// empty if no file was used
+func File() string {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return configFileUsed
+}
+
+func (config *configSchema) load() error {
 	v := viper.New()
 
 	err := v.BindPFlags(pflag.CommandLine)
@@ -147,10 +227,13 @@ func (config *configSchema) Read() {
 
 	err = v.ReadInConfig()
 	if v.ConfigFileUsed() != "" && err != nil {
-		log.Fatal(err)
+		return err
 	}
+	lock.Lock()
+	configFileUsed = v.ConfigFileUsed()
+	lock.Unlock()
 
-	config.Alertmanager.Servers = []alertmanagerConfig{}
+	config.Alertmanager.Servers = []AlertmanagerConfig{}
 	config.Alertmanager.Interval = v.GetDuration("alertmanager.interval")
 	config.Annotations.Default.Hidden = v.GetBool("annotations.default.hidden")
 	config.Annotations.Hidden = v.GetStringSlice("annotations.hidden")
@@ -161,9 +244,11 @@ func (config *configSchema) Read() {
 	config.Custom.JS = v.GetString("custom.js")
 	config.Debug = v.GetBool("debug")
 	config.Filters.Default = v.GetStringSlice("filters.default")
+	config.Grid.GroupBy = v.GetStringSlice("grid.groupBy")
 	config.Grid.Sorting.Order = v.GetString("grid.sorting.order")
 	config.Grid.Sorting.Reverse = v.GetBool("grid.sorting.reverse")
 	config.Grid.Sorting.Label = v.GetString("grid.sorting.label")
+	config.Health.MinHealthyUpstreams = v.GetInt("health.min_healthy_upstreams")
 	config.Karma.Name = v.GetString("karma.name")
 	config.Labels.Color.Custom = CustomLabelColors{}
 	config.Labels.Color.Static = v.GetStringSlice("labels.color.static")
@@ -193,38 +278,77 @@ func (config *configSchema) Read() {
 	if config.SilenceForm.Author.PopulateFromHeader.ValueRegex != "" {
 		_, err = regexp.Compile(config.SilenceForm.Author.PopulateFromHeader.ValueRegex)
 		if err != nil {
-			log.Fatalf("Invalid regex for silenceform.author.populate_from_header.value_re: %s", err.Error())
+			return fmt.Errorf("invalid regex for silenceform.author.populate_from_header.value_re: %s", err.Error())
 		}
 	}
 
 	err = v.UnmarshalKey("alertmanager.servers", &config.Alertmanager.Servers)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	for i, s := range config.Alertmanager.Servers {
-		if s.Timeout.Seconds() == 0 {
-			config.Alertmanager.Servers[i].Timeout = v.GetDuration("alertmanager.timeout")
+		config.Alertmanager.Servers[i] = config.withServerDefaults(v, s)
+	}
+
+	err = v.UnmarshalKey("alertmanager.discovery", &config.Alertmanager.Discovery)
+	if err != nil {
+		return err
+	}
+	for i, d := range config.Alertmanager.Discovery {
+		if d.Name == "" {
+			return fmt.Errorf("alertmanager discovery is missing 'name'")
+		}
+		if len(d.FileSD.Files) == 0 && len(d.DNSSD.Names) == 0 {
+			return fmt.Errorf("alertmanager discovery '%s' needs either 'file_sd:files' or 'dns_sd:names'", d.Name)
 		}
+		if len(d.DNSSD.Names) > 0 {
+			switch strings.ToUpper(d.DNSSD.Type) {
+			case "", "SRV":
+				config.Alertmanager.Discovery[i].DNSSD.Type = "SRV"
+			case "A", "AAAA":
+				if d.DNSSD.Port <= 0 {
+					return fmt.Errorf("alertmanager discovery '%s' needs 'dns_sd:port' for %s records", d.Name, d.DNSSD.Type)
+				}
+				config.Alertmanager.Discovery[i].DNSSD.Type = strings.ToUpper(d.DNSSD.Type)
+			default:
+				return fmt.Errorf("invalid dns_sd:type '%s' for alertmanager discovery '%s', allowed options: SRV, A, AAAA", d.DNSSD.Type, d.Name)
+			}
+		}
+		if d.Scheme == "" {
+			config.Alertmanager.Discovery[i].Scheme = "http"
+		}
+		if d.RefreshInterval.Seconds() == 0 {
+			config.Alertmanager.Discovery[i].RefreshInterval = time.Second * 30
+		}
+		config.Alertmanager.Discovery[i].AlertmanagerConfig = config.withServerDefaults(v, d.AlertmanagerConfig)
 	}
 
 	err = v.UnmarshalKey("jira", &config.JIRA)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	for _, rule := range config.JIRA {
+		if rule.Regex == "" || rule.URI == "" {
+			return fmt.Errorf("invalid JIRA rule with regexp '%s' and url '%s'", rule.Regex, rule.URI)
+		}
+		if _, err = regexp.Compile(rule.Regex); err != nil {
+			return fmt.Errorf("failed to parse JIRA rule regex '%s': %s", rule.Regex, err)
+		}
 	}
 
 	err = v.UnmarshalKey("labels.color.custom", &config.Labels.Color.Custom)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	for labelName, customColors := range config.Labels.Color.Custom {
 		for i, customColor := range customColors {
 			if customColor.Value == "" && customColor.ValueRegex == "" {
-				log.Fatalf("Custom label color for '%s' is missing 'value' or 'value_re'", labelName)
+				return fmt.Errorf("custom label color for '%s' is missing 'value' or 'value_re'", labelName)
 			}
 			if customColor.ValueRegex != "" {
 				config.Labels.Color.Custom[labelName][i].CompiledRegex, err = regexp.Compile(customColor.ValueRegex)
 				if err != nil {
-					log.Fatalf("Failed to parse custom color regex rule '%s' for '%s' label: %s", customColor.ValueRegex, labelName, err)
+					return fmt.Errorf("failed to parse custom color regex rule '%s' for '%s' label: %s", customColor.ValueRegex, labelName, err)
 				}
 			}
 		}
@@ -232,15 +356,40 @@ func (config *configSchema) Read() {
 
 	err = v.UnmarshalKey("grid.sorting.customValues.labels", &config.Grid.Sorting.CustomValues.Labels)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	if !slices.StringInSlice([]string{"disabled", "startsAt", "label"}, config.Grid.Sorting.Order) {
-		log.Fatalf("Invalid grid.sorting.order value '%s', allowed options: disabled, startsAt, label", config.Grid.Sorting.Order)
+		return fmt.Errorf("invalid grid.sorting.order value '%s', allowed options: disabled, startsAt, label", config.Grid.Sorting.Order)
+	}
+
+	for _, label := range config.Grid.GroupBy {
+		if strings.HasPrefix(label, "@") && !slices.StringInSlice(GroupBySpecialLabels, label) {
+			return fmt.Errorf("invalid grid.groupBy value '%s', allowed special labels: %s", label, strings.Join(GroupBySpecialLabels, ", "))
+		}
+	}
+
+	config.Views = []ViewConfig{}
+	err = v.UnmarshalKey("views", &config.Views)
+	if err != nil {
+		return err
+	}
+	viewNames := map[string]bool{}
+	for _, view := range config.Views {
+		if view.Name == "" {
+			return fmt.Errorf("view is missing 'name'")
+		}
+		if viewNames[view.Name] {
+			return fmt.Errorf("duplicated view name '%s'", view.Name)
+		}
+		viewNames[view.Name] = true
+		if view.Sorting.Order != "" && !slices.StringInSlice([]string{"disabled", "startsAt", "label"}, view.Sorting.Order) {
+			return fmt.Errorf("invalid sorting:order value '%s' for view '%s', allowed options: disabled, startsAt, label", view.Sorting.Order, view.Name)
+		}
 	}
 
 	if !slices.StringInSlice([]string{"expanded", "collapsed", "collapsedOnMobile"}, config.UI.CollapseGroups) {
-		log.Fatalf("Invalid ui.collapseGroups value '%s', allowed options: expanded, collapsed, collapsedOnMobile", config.UI.CollapseGroups)
+		return fmt.Errorf("invalid ui.collapseGroups value '%s', allowed options: expanded, collapsed, collapsedOnMobile", config.UI.CollapseGroups)
 	}
 
 	// FIXME workaround  for https://github.com/prymitive/karma/issues/507
@@ -253,12 +402,12 @@ func (config *configSchema) Read() {
 		var rawConfigFile []byte
 		rawConfigFile, err = ioutil.ReadFile(v.ConfigFileUsed())
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		err = yaml.Unmarshal(rawConfigFile, &raw)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		config.Grid.Sorting.CustomValues.Labels = raw.Grid.Sorting.CustomValues.Labels
@@ -267,17 +416,49 @@ func (config *configSchema) Read() {
 	// accept single Alertmanager server from flag/env if nothing is set yet
 	if len(config.Alertmanager.Servers) == 0 && v.GetString("alertmanager.uri") != "" {
 		log.Info("Using simple config with a single Alertmanager server")
-		config.Alertmanager.Servers = []alertmanagerConfig{
-			{
-				Name:        v.GetString("alertmanager.name"),
-				URI:         v.GetString("alertmanager.uri"),
-				ExternalURI: v.GetString("alertmanager.external_uri"),
-				Timeout:     v.GetDuration("alertmanager.timeout"),
-				Proxy:       v.GetBool("alertmanager.proxy"),
-				Headers:     make(map[string]string),
-			},
+		server := AlertmanagerConfig{
+			Name:        v.GetString("alertmanager.name"),
+			URI:         v.GetString("alertmanager.uri"),
+			ExternalURI: v.GetString("alertmanager.external_uri"),
+			Timeout:     v.GetDuration("alertmanager.timeout"),
+			Interval:    config.Alertmanager.Interval,
+			GracePeriod: v.GetDuration("alertmanager.grace_period"),
+			Proxy:       v.GetBool("alertmanager.proxy"),
+			Headers:     make(map[string]string),
 		}
+		config.Alertmanager.Servers = []AlertmanagerConfig{config.withServerDefaults(v, server)}
+	}
+
+	return nil
+}
+
+// withServerDefaults returns a copy of Alertmanager server config with all
+// unset keys populated using global defaults
+func (config *configSchema) withServerDefaults(v *viper.Viper, s AlertmanagerConfig) AlertmanagerConfig {
+	if s.Timeout.Seconds() == 0 {
+		s.Timeout = v.GetDuration("alertmanager.timeout")
+	}
+	if s.Interval.Seconds() == 0 {
+		s.Interval = config.Alertmanager.Interval
+	}
+	if s.GracePeriod.Seconds() == 0 {
+		s.GracePeriod = v.GetDuration("alertmanager.grace_period")
+	}
+	if s.Retry.Attempts == nil {
+		attempts := v.GetInt("alertmanager.retry.attempts")
+		s.Retry.Attempts = &attempts
+	}
+	if s.Retry.Backoff.Seconds() == 0 {
+		s.Retry.Backoff = v.GetDuration("alertmanager.retry.backoff")
+	}
+	if s.CircuitBreaker.Failures == nil {
+		failures := v.GetInt("alertmanager.circuit_breaker.failures")
+		s.CircuitBreaker.Failures = &failures
+	}
+	if s.CircuitBreaker.Cooldown.Seconds() == 0 {
+		s.CircuitBreaker.Cooldown = v.GetDuration("alertmanager.circuit_breaker.cooldown")
 	}
+	return s
 }
 
 // LogValues will dump runtime config to logs
@@ -286,17 +467,24 @@ func (config *configSchema) LogValues() {
 	cfg := configSchema(*config)
 
 	// replace passwords in Alertmanager URIs with 'xxx'
-	servers := []alertmanagerConfig{}
+	servers := []AlertmanagerConfig{}
 	for _, s := range cfg.Alertmanager.Servers {
-		server := alertmanagerConfig{
+		server := AlertmanagerConfig{
 			Name:        s.Name,
+			Cluster:     s.Cluster,
 			URI:         uri.SanitizeURI(s.URI),
 			ExternalURI: uri.SanitizeURI(s.ExternalURI),
 			Timeout:     s.Timeout,
+			Interval:    s.Interval,
+			GracePeriod: s.GracePeriod,
 			TLS:         s.TLS,
 			Proxy:       s.Proxy,
+			Version:     s.Version,
 			Headers:     s.Headers,
+			Matchers:    s.Matchers,
 		}
+		server.Retry = s.Retry
+		server.CircuitBreaker = s.CircuitBreaker
 		servers = append(servers, server)
 	}
 	cfg.Alertmanager.Servers = servers
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"io/ioutil"
 	"os"
 	"testing"
 	"time"
@@ -21,7 +22,12 @@ func resetEnv() {
 		"ALERTMANAGER_URI",
 		"ALERTMANAGER_EXTERNAL_URI",
 		"ALERTMANAGER_NAME",
-		"ALERTMANAGET_TIMEOUT",
+		"ALERTMANAGER_TIMEOUT",
+		"ALERTMANAGER_GRACE_PERIOD",
+		"ALERTMANAGER_RETRY_ATTEMPTS",
+		"ALERTMANAGER_RETRY_BACKOFF",
+		"ALERTMANAGER_CIRCUIT_BREAKER_FAILURES",
+		"ALERTMANAGER_CIRCUIT_BREAKER_COOLDOWN",
 		"ANNOTATIONS_DEFAULT_HIDDEN",
 		"ANNOTATIONS_HIDDEN",
 		"ANNOTATIONS_VISIBLE",
@@ -30,6 +36,9 @@ func resetEnv() {
 		"CUSTOM_JS",
 		"DEBUG",
 		"FILTERS_DEFAULT",
+		"GRID_GROUPBY",
+		"GRID_SORTING_ORDER",
+		"HEALTH_MIN_HEALTHY_UPSTREAMS",
 		"KARMA_NAME",
 		"LABELS_COLOR_STATIC",
 		"LABELS_COLOR_UNIQUE",
@@ -44,6 +53,8 @@ func resetEnv() {
 		"RECEIVERS_STRIP",
 		"SENTRY_PRIVATE",
 		"SENTRY_PUBLIC",
+		"SILENCEFORM_AUTHOR_POPULATE_FROM_HEADER_VALUE_RE",
+		"UI_COLLAPSEGROUPS",
 
 		"HOST",
 		"PORT",
@@ -59,16 +70,28 @@ func testReadConfig(t *testing.T) {
   interval: 1s
   servers:
   - name: default
+    cluster: ""
     uri: http://localhost
     external_uri: http://example.com
     timeout: 40s
+    interval: 1s
+    grace_period: 0s
+    retry:
+      attempts: 0
+      backoff: 1s
+    circuit_breaker:
+      failures: 0
+      cooldown: 5m0s
     proxy: false
+    version: ""
     tls:
       ca: ""
       cert: ""
       key: ""
       insecureSkipVerify: false
     headers: {}
+    matchers: []
+  discovery: []
 annotations:
   default:
     hidden: true
@@ -86,12 +109,15 @@ filters:
   - '@state=active'
   - foo=bar
 grid:
+  groupBy: []
   sorting:
     order: startsAt
     reverse: true
     label: alertname
     customValues:
       labels: {}
+health:
+  min_healthy_upstreams: 2
 karma:
   name: another karma
 labels:
@@ -139,6 +165,7 @@ ui:
   minimalGroupWidth: 420
   alertsPerGroup: 5
   collapseGroups: collapsedOnMobile
+views: []
 `
 
 	configDump, err := yaml.Marshal(Config)
@@ -174,6 +201,7 @@ func TestReadConfig(t *testing.T) {
 	os.Setenv("CUSTOM_JS", "/custom.js")
 	os.Setenv("DEBUG", "true")
 	os.Setenv("FILTERS_DEFAULT", "@state=active foo=bar")
+	os.Setenv("HEALTH_MIN_HEALTHY_UPSTREAMS", "2")
 	os.Setenv("KARMA_NAME", "another karma")
 	os.Setenv("LABELS_COLOR_STATIC", "a bb ccc")
 	os.Setenv("LABELS_COLOR_UNIQUE", "f gg")
@@ -209,6 +237,9 @@ func TestReadSimpleConfig(t *testing.T) {
 		if Config.Alertmanager.Interval != time.Minute*3 {
 			t.Errorf("Expect Alertmanager timeout '%v' got '%v'", time.Minute*3, Config.Alertmanager.Interval)
 		}
+		if am.Interval != time.Minute*3 {
+			t.Errorf("Expect Alertmanager interval '%v' got '%v'", time.Minute*3, am.Interval)
+		}
 	}
 }
 
@@ -287,6 +318,22 @@ func TestInvalidGridSortingOrder(t *testing.T) {
 	}
 }
 
+func TestInvalidGridGroupBy(t *testing.T) {
+	resetEnv()
+	os.Setenv("GRID_GROUPBY", "cluster @state")
+
+	log.SetLevel(log.PanicLevel)
+	defer func() { log.StandardLogger().ExitFunc = nil }()
+	var wasFatal bool
+	log.StandardLogger().ExitFunc = func(int) { wasFatal = true }
+
+	Config.Read()
+
+	if !wasFatal {
+		t.Error("Invalid grid.groupBy value didn't cause log.Fatal()")
+	}
+}
+
 func TestInvalidUICollapseGroups(t *testing.T) {
 	resetEnv()
 	os.Setenv("UI_COLLAPSEGROUPS", "foo")
@@ -302,3 +349,265 @@ func TestInvalidUICollapseGroups(t *testing.T) {
 		t.Error("Invalid ui.collapseGroups value didn't cause log.Fatal()")
 	}
 }
+
+func TestReload(t *testing.T) {
+	resetEnv()
+	defer resetEnv()
+	log.SetLevel(log.ErrorLevel)
+	os.Setenv("ALERTMANAGER_URI", "http://localhost")
+	os.Setenv("LABELS_COLOR_UNIQUE", "foo")
+	Config.Read()
+
+	os.Setenv("ALERTMANAGER_URI", "http://reloaded.localhost")
+	os.Setenv("LABELS_COLOR_UNIQUE", "bar")
+	os.Setenv("KARMA_NAME", "reloaded")
+	if err := Config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if Config.Alertmanager.Servers[0].URI != "http://reloaded.localhost" {
+		t.Errorf("Alertmanager URI wasn't reloaded, got '%s'", Config.Alertmanager.Servers[0].URI)
+	}
+	if colors := Config.LabelColors(); len(colors.Unique) != 1 || colors.Unique[0] != "bar" {
+		t.Errorf("Unique label colors weren't reloaded, got %v", colors.Unique)
+	}
+	if Config.Karma.Name == "reloaded" {
+		t.Error("karma.name was modified by reload")
+	}
+
+	// invalid config must not modify current values
+	os.Setenv("ALERTMANAGER_URI", "http://invalid.localhost")
+	os.Setenv("GRID_SORTING_ORDER", "foo")
+	if err := Config.Reload(); err == nil {
+		t.Error("Reload() with invalid grid.sorting.order didn't return any error")
+	}
+	if Config.Alertmanager.Servers[0].URI != "http://reloaded.localhost" {
+		t.Errorf("Alertmanager URI was modified by invalid reload, got '%s'", Config.Alertmanager.Servers[0].URI)
+	}
+}
+
+type discoveryConfigTest struct {
+	config  string
+	isError bool
+}
+
+var discoveryConfigTests = []discoveryConfigTest{
+	{
+		config: `alertmanager:
+  discovery:
+    - name: dns
+      dns_sd:
+        names: [_alertmanager._tcp.example.com]
+`,
+	},
+	{
+		config: `alertmanager:
+  discovery:
+    - dns_sd:
+        names: [_alertmanager._tcp.example.com]
+`,
+		isError: true,
+	},
+	{
+		config: `alertmanager:
+  discovery:
+    - name: empty
+`,
+		isError: true,
+	},
+	{
+		config: `alertmanager:
+  discovery:
+    - name: a
+      dns_sd:
+        names: [alertmanager.example.com]
+        type: A
+`,
+		isError: true,
+	},
+	{
+		config: `alertmanager:
+  discovery:
+    - name: mx
+      dns_sd:
+        names: [alertmanager.example.com]
+        type: MX
+`,
+		isError: true,
+	},
+}
+
+func TestDiscoveryConfig(t *testing.T) {
+	resetEnv()
+	defer resetEnv()
+	log.SetLevel(log.ErrorLevel)
+
+	f, err := ioutil.TempFile("", "karma-discovery-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+	os.Setenv("CONFIG_FILE", f.Name())
+
+	for _, testCase := range discoveryConfigTests {
+		if err = ioutil.WriteFile(f.Name(), []byte(testCase.config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg := configSchema{}
+		err = cfg.load()
+		if (err != nil) != testCase.isError {
+			t.Errorf("Invalid load() result for config:\n%s\nerror: %v", testCase.config, err)
+		}
+		if err != nil {
+			continue
+		}
+		d := cfg.Alertmanager.Discovery[0]
+		if d.DNSSD.Type != "SRV" || d.Scheme != "http" || d.RefreshInterval != time.Second*30 {
+			t.Errorf("Discovery defaults not set: %+v", d)
+		}
+		if d.Timeout != time.Second*40 || d.Interval != time.Minute {
+			t.Errorf("Alertmanager defaults not set for discovery: %+v", d.AlertmanagerConfig)
+		}
+	}
+}
+
+func TestServerDefaults(t *testing.T) {
+	resetEnv()
+	defer resetEnv()
+	log.SetLevel(log.ErrorLevel)
+
+	f, err := ioutil.TempFile("", "karma-defaults-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+	os.Setenv("CONFIG_FILE", f.Name())
+
+	config := `alertmanager:
+  retry:
+    attempts: 3
+  circuit_breaker:
+    failures: 5
+  servers:
+    - name: global
+      uri: http://localhost:9093
+    - name: disabled
+      uri: http://localhost:9094
+      retry:
+        attempts: 0
+      circuit_breaker:
+        failures: 0
+    - name: custom
+      uri: http://localhost:9095
+      retry:
+        attempts: 1
+      circuit_breaker:
+        failures: 2
+`
+	if err = ioutil.WriteFile(f.Name(), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := configSchema{}
+	if err = cfg.load(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]int{
+		"global":   {3, 5},
+		"disabled": {0, 0},
+		"custom":   {1, 2},
+	}
+	for _, s := range cfg.Alertmanager.Servers {
+		if s.Retry.Attempts == nil || s.CircuitBreaker.Failures == nil {
+			t.Errorf("[%s] Defaults not set: %+v", s.Name, s)
+			continue
+		}
+		if *s.Retry.Attempts != expected[s.Name][0] {
+			t.Errorf("[%s] retry:attempts is %d, expected %d", s.Name, *s.Retry.Attempts, expected[s.Name][0])
+		}
+		if *s.CircuitBreaker.Failures != expected[s.Name][1] {
+			t.Errorf("[%s] circuit_breaker:failures is %d, expected %d", s.Name, *s.CircuitBreaker.Failures, expected[s.Name][1])
+		}
+	}
+}
+
+type viewConfigTest struct {
+	config  string
+	isError bool
+}
+
+var viewConfigTests = []viewConfigTest{
+	{
+		config: `views:
+  - name: db-oncall
+    title: Database on-call
+    filters:
+      - team=db
+      - "@state=active"
+    sorting:
+      order: label
+      reverse: false
+      label: cluster
+`,
+	},
+	{
+		config: `views:
+  - title: Database on-call
+    filters: [team=db]
+`,
+		isError: true,
+	},
+	{
+		config: `views:
+  - name: db
+    filters: [team=db]
+  - name: db
+    filters: [team=storage]
+`,
+		isError: true,
+	},
+	{
+		config: `views:
+  - name: db
+    sorting:
+      order: foo
+`,
+		isError: true,
+	},
+}
+
+func TestViewConfig(t *testing.T) {
+	resetEnv()
+	defer resetEnv()
+	log.SetLevel(log.ErrorLevel)
+
+	f, err := ioutil.TempFile("", "karma-views-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+	os.Setenv("CONFIG_FILE", f.Name())
+
+	for _, testCase := range viewConfigTests {
+		if err = ioutil.WriteFile(f.Name(), []byte(testCase.config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg := configSchema{}
+		err = cfg.load()
+		if (err != nil) != testCase.isError {
+			t.Errorf("Invalid load() result for config:\n%s\nerror: %v", testCase.config, err)
+		}
+		if err != nil {
+			continue
+		}
+		v := cfg.Views[0]
+		if v.Name != "db-oncall" || v.Title != "Database on-call" || len(v.Filters) != 2 {
+			t.Errorf("Invalid view config: %+v", v)
+		}
+		if v.Sorting.Order != "label" || v.Sorting.Label != "cluster" || v.Sorting.Reverse == nil || *v.Sorting.Reverse {
+			t.Errorf("Invalid view sorting config: %+v", v.Sorting)
+		}
+	}
+}
@@ -5,19 +5,65 @@ import (
 	"time"
 )
 
-type alertmanagerConfig struct {
+// AlertmanagerConfig describes a single Alertmanager server
+type AlertmanagerConfig struct {
 	Name        string
+	Cluster     string
 	URI         string
 	ExternalURI string `yaml:"external_uri" mapstructure:"external_uri"`
 	Timeout     time.Duration
-	Proxy       bool
-	TLS         struct {
+	Interval    time.Duration
+	GracePeriod time.Duration `yaml:"grace_period" mapstructure:"grace_period"`
+	// Attempts and Failures are pointers so we can tell if those were set to 0
+	// for this server or if we should use global values
+	Retry struct {
+		Attempts *int
+		Backoff  time.Duration
+	}
+	CircuitBreaker struct {
+		Failures *int
+		Cooldown time.Duration
+	} `yaml:"circuit_breaker" mapstructure:"circuit_breaker"`
+	Proxy   bool
+	Version string
+	TLS     struct {
 		CA                 string
 		Cert               string
 		Key                string
 		InsecureSkipVerify bool `yaml:"insecureSkipVerify"  mapstructure:"insecureSkipVerify"`
 	}
-	Headers map[string]string
+	Headers  map[string]string
+	Matchers []string
+}
+
+// DiscoveryConfig describes how to discover Alertmanager servers, every
+// discovered server will use settings from the embedded AlertmanagerConfig
+type DiscoveryConfig struct {
+	AlertmanagerConfig `yaml:",inline" mapstructure:",squash"`
+	RefreshInterval    time.Duration `yaml:"refresh_interval" mapstructure:"refresh_interval"`
+	Scheme             string
+	Path               string
+	FileSD             struct {
+		Files []string
+	} `yaml:"file_sd" mapstructure:"file_sd"`
+	DNSSD struct {
+		Names []string
+		Type  string
+		Port  int
+	} `yaml:"dns_sd" mapstructure:"dns_sd"`
+}
+
+// ViewConfig describes a named list of filters with optional sort settings
+// that can be selected using the view query argument
+type ViewConfig struct {
+	Name    string
+	Title   string
+	Filters []string
+	Sorting struct {
+		Order   string
+		Reverse *bool
+		Label   string
+	}
 }
 
 type jiraRule struct {
@@ -34,10 +80,18 @@ type CustomLabelColor struct {
 
 type CustomLabelColors map[string][]CustomLabelColor
 
+// LabelColorRules describes how label colors are generated
+type LabelColorRules struct {
+	Custom CustomLabelColors
+	Static []string
+	Unique []string
+}
+
 type configSchema struct {
 	Alertmanager struct {
-		Interval time.Duration
-		Servers  []alertmanagerConfig
+		Interval  time.Duration
+		Servers   []AlertmanagerConfig
+		Discovery []DiscoveryConfig
 	}
 	Annotations struct {
 		Default struct {
@@ -57,6 +111,7 @@ type configSchema struct {
 		Default []string
 	}
 	Grid struct {
+		GroupBy []string `yaml:"groupBy" mapstructure:"groupBy"`
 		Sorting struct {
 			Order        string
 			Reverse      bool
@@ -66,17 +121,16 @@ type configSchema struct {
 			} `yaml:"customValues" mapstructure:"customValues"`
 		}
 	}
+	Health struct {
+		MinHealthyUpstreams int `yaml:"min_healthy_upstreams" mapstructure:"min_healthy_upstreams"`
+	}
 	Karma struct {
 		Name string
 	}
 	Labels struct {
 		Keep  []string
 		Strip []string
-		Color struct {
-			Custom CustomLabelColors
-			Static []string
-			Unique []string
-		}
+		Color LabelColorRules
 	}
 	Listen struct {
 		Address string
@@ -116,4 +170,5 @@ type configSchema struct {
 		AlertsPerGroup      int    `yaml:"alertsPerGroup" mapstructure:"alertsPerGroup"`
 		CollapseGroups      string `yaml:"collapseGroups" mapstructure:"collapseGroups"`
 	}
+	Views []ViewConfig
 }
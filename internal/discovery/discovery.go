@@ -0,0 +1,152 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// RecordSRV will discover targets using DNS SRV records
+	RecordSRV = "SRV"
+	// RecordA will discover targets using DNS A records
+	RecordA = "A"
+	// RecordAAAA will discover targets using DNS AAAA records
+	RecordAAAA = "AAAA"
+)
+
+// those are variables so tests can replace them with fake resolvers
+var (
+	lookupSRV = net.DefaultResolver.LookupSRV
+	lookupIP  = func(ctx context.Context, network, host string) ([]net.IP, error) {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		ips := []net.IP{}
+		for _, addr := range addrs {
+			isV4 := addr.IP.To4() != nil
+			if (network == "ip4" && isV4) || (network == "ip6" && !isV4) {
+				ips = append(ips, addr.IP)
+			}
+		}
+		return ips, nil
+	}
+)
+
+// targetGroup is a single entry in the file_sd file, same format as used by
+// Prometheus, we only need targets so any labels are ignored
+type targetGroup struct {
+	Targets []string `yaml:"targets"`
+}
+
+// FileTargets reads all targets from Prometheus file_sd style files, file
+// paths can use glob patterns, files can use either YAML or JSON format
+func FileTargets(patterns []string) ([]string, error) {
+	targets := []string{}
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file pattern '%s': %s", pattern, err)
+		}
+		for _, path := range paths {
+			var content []byte
+			content, err = ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			groups := []targetGroup{}
+			if err = yaml.Unmarshal(content, &groups); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %s", path, err)
+			}
+			for _, group := range groups {
+				targets = append(targets, group.Targets...)
+			}
+		}
+	}
+	return uniqueTargets(targets), nil
+}
+
+// DNSTargets resolves all names using given DNS record type and returns all
+// targets as host:port strings, port is only used for A and AAAA records
+func DNSTargets(names []string, recordType string, port int, timeout time.Duration) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	targets := []string{}
+	for _, name := range names {
+		switch strings.ToUpper(recordType) {
+		case RecordSRV:
+			_, records, err := lookupSRV(ctx, "", "", name)
+			if err != nil {
+				return nil, err
+			}
+			for _, record := range records {
+				host := strings.TrimSuffix(record.Target, ".")
+				targets = append(targets, net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+			}
+		case RecordA, RecordAAAA:
+			network := "ip4"
+			if strings.ToUpper(recordType) == RecordAAAA {
+				network = "ip6"
+			}
+			ips, err := lookupIP(ctx, network, name)
+			if err != nil {
+				return nil, err
+			}
+			for _, ip := range ips {
+				targets = append(targets, net.JoinHostPort(ip.String(), strconv.Itoa(port)))
+			}
+		default:
+			return nil, fmt.Errorf("unsupported DNS record type '%s'", recordType)
+		}
+	}
+	return uniqueTargets(targets), nil
+}
+
+// TargetURI returns the URI of the Alertmanager for given target, targets
+// can be full URIs or host:port strings, in which case scheme and path will
+// be used to build the URI
+func TargetURI(target, scheme, path string) string {
+	if strings.Contains(target, "://") {
+		return target
+	}
+	u := url.URL{
+		Scheme: scheme,
+		Host:   target,
+		Path:   path,
+	}
+	return u.String()
+}
+
+// TargetName returns the name for the Alertmanager discovered using given
+// target, it uses the host:port part of the target prefixed with the name of
+// the discovery config
+func TargetName(prefix, target string) string {
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		target = u.Host
+	}
+	return fmt.Sprintf("%s-%s", prefix, target)
+}
+
+func uniqueTargets(targets []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, target := range targets {
+		if !seen[target] {
+			seen[target] = true
+			unique = append(unique, target)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
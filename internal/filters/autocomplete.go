@@ -21,7 +21,18 @@ func BuildAutocomplete(alerts []models.Alert) []models.Autocomplete {
 	acHints := map[string]models.Autocomplete{}
 	for _, filterConfig := range AllFilters {
 		if filterConfig.Autocomplete != nil {
-			for _, hint := range filterConfig.Autocomplete(filterConfig.Label, filterConfig.SupportedOperators, alerts) {
+			// anchored regex and "or equal" operators are meant to be typed
+			// by hand, hints for those would only duplicate regex, "<" and ">"
+			// hints
+			operators := []string{}
+			for _, operator := range filterConfig.SupportedOperators {
+				switch operator {
+				case anchoredRegexpOperator, negativeAnchoredRegexOperator, moreThanOrEqualOperator, lessThanOrEqualOperator:
+				default:
+					operators = append(operators, operator)
+				}
+			}
+			for _, hint := range filterConfig.Autocomplete(filterConfig.Label, operators, alerts) {
 				acHints[hint.Value] = hint
 			}
 		}
@@ -24,6 +24,10 @@ var acTests = []acTest{
 			"@age\u003c10m",
 			"@age\u003c1h",
 			"@age\u003e10m",
+			"@group_limit=10",
+			"@group_limit=50",
+			"@group_size\u003e10",
+			"@group_size\u003e50",
 			"@limit=10",
 			"@limit=50",
 		},
@@ -36,10 +40,13 @@ var acTests = []acTest{
 					"foo":    "bar",
 					"number": "1",
 				},
+				Annotations: models.Annotations{
+					{Name: "summary", Value: "foo bar"},
+				},
 				Receiver: "default",
 				Alertmanager: []models.AlertmanagerInstance{
-					{Name: "am1"},
-					{Name: "am2"},
+					{Name: "am1", ClusterName: "ha"},
+					{Name: "am2", ClusterName: "ha"},
 				},
 			},
 			{
@@ -61,7 +68,8 @@ var acTests = []acTest{
 							},
 						}},
 				},
-				SilencedBy: []string{"1234567890"},
+				SilencedBy:  []string{"1234567890"},
+				InhibitedBy: []string{"fedcba9876543210"},
 			},
 		},
 		Expected: []string{
@@ -73,8 +81,30 @@ var acTests = []acTest{
 			"@alertmanager!=am2",
 			"@alertmanager=am1",
 			"@alertmanager=am2",
+			"@annotation.summary!=foo bar",
+			"@annotation.summary=foo bar",
+			"@cluster!=ha",
+			"@cluster=ha",
+			"@group_limit=10",
+			"@group_limit=50",
+			"@group_size\u003e10",
+			"@group_size\u003e50",
+			"@group_state!=active",
+			"@group_state!=suppressed",
+			"@group_state=active",
+			"@group_state=suppressed",
+			"@has=foo",
+			"@has=number",
+			"@inhibited!=false",
+			"@inhibited!=true",
+			"@inhibited=false",
+			"@inhibited=true",
+			"@inhibited_by!=fedcba9876543210",
+			"@inhibited_by=fedcba9876543210",
 			"@limit=10",
 			"@limit=50",
+			"@missing=foo",
+			"@missing=number",
 			"@receiver!=default",
 			"@receiver!=not default",
 			"@receiver!~default",
@@ -87,6 +117,14 @@ var acTests = []acTest{
 			"@silence_author!~me@example.com",
 			"@silence_author=me@example.com",
 			"@silence_author=~me@example.com",
+			"@silence_age\u003c1d",
+			"@silence_age\u003c1h",
+			"@silence_age\u003e1d",
+			"@silence_age\u003e1h",
+			"@silence_expires\u003c1d",
+			"@silence_expires\u003c1h",
+			"@silence_expires\u003e1d",
+			"@silence_expires\u003e1h",
 			"@silence_id!=1234567890",
 			"@silence_id=1234567890",
 			"@silence_jira!=JIRA-1",
@@ -0,0 +1,21 @@
+package filters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseDuration works like time.ParseDuration but it also accepts a number of
+// days, like 2d, since those are common when dealing with alerts and silences
+func parseDuration(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s'", value)
+		}
+		return time.Duration(days) * time.Hour * 24, nil
+	}
+	return time.ParseDuration(value)
+}
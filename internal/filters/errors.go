@@ -0,0 +1,13 @@
+package filters
+
+// error codes returned for invalid filters, those are part of the API so
+// they should never be renamed
+const (
+	errorMissingValue        string = "missing_value"
+	errorUnknownFilter       string = "unknown_filter"
+	errorUnknownOperator     string = "unknown_operator"
+	errorUnsupportedOperator string = "unsupported_operator"
+	errorInvalidRegex        string = "invalid_regex"
+	errorInvalidValue        string = "invalid_value"
+	errorInvalidExpression   string = "invalid_expression"
+)
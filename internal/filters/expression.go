@@ -0,0 +1,299 @@
+package filters
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/prymitive/karma/internal/models"
+)
+
+const (
+	tokenTerm = iota
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type exprToken struct {
+	kind int
+	text string
+}
+
+// termRegex matches terms with the "name op" prefix, only those can be used
+// in expressions
+var termRegex = regexp.MustCompile(fmt.Sprintf("^(%s)(%s)", filterRegex, matcherRegex))
+
+// tokenizeExpression splits filter expression into tokens, operators are only
+// recognized at the start of a term, so any "|", "(" or ")" that is part of
+// the filter value (like foo=~(a|b)) is kept as is, ")" is also only used as
+// an operator if it closes a group opened earlier
+func tokenizeExpression(expression string) []exprToken {
+	tokens := []exprToken{}
+	runes := []rune(expression)
+	groups := 0
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '|':
+			tokens = append(tokens, exprToken{kind: tokenOr, text: "|"})
+			i++
+		case r == '(':
+			tokens = append(tokens, exprToken{kind: tokenOpen, text: "("})
+			groups++
+			i++
+		case r == ')' && groups > 0:
+			tokens = append(tokens, exprToken{kind: tokenClose, text: ")"})
+			groups--
+			i++
+		case r == '!' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != '=' && runes[i+1] != '~':
+			tokens = append(tokens, exprToken{kind: tokenNot, text: "!"})
+			i++
+		default:
+			// read the term until whitespace or a closing parenthesis that
+			// doesn't belong to the value but closes an open group
+			depth := 0
+			start := i
+			for ; i < len(runes); i++ {
+				if unicode.IsSpace(runes[i]) {
+					break
+				}
+				if runes[i] == '(' {
+					depth++
+				}
+				if runes[i] == ')' {
+					if depth == 0 && groups > 0 {
+						break
+					}
+					if depth > 0 {
+						depth--
+					}
+				}
+			}
+			tokens = append(tokens, exprToken{kind: tokenTerm, text: string(runes[start:i])})
+		}
+	}
+	return tokens
+}
+
+// isExpression returns true if there's any operator in the list of tokens
+// and all terms are "name op value" filters, if not then we're dealing with
+// a single filter, this way plain text like "foo (bar)" or filter values like
+// "@silence_comment=foo | bar" are used as they are
+func isExpression(tokens []exprToken) bool {
+	hasOperator := false
+	for _, token := range tokens {
+		if token.kind != tokenTerm {
+			hasOperator = true
+		} else if !termRegex.MatchString(token.text) {
+			return false
+		}
+	}
+	return hasOperator
+}
+
+// exprNode is a single node of the parsed filter expression
+type exprNode interface {
+	match(alert *models.Alert, matches int) bool
+}
+
+type termNode struct {
+	filter FilterT
+}
+
+func (n *termNode) match(alert *models.Alert, matches int) bool {
+	return n.filter.Match(alert, matches)
+}
+
+// andNode and orNode will always evaluate all child nodes, so hits are
+// counted for every term in the expression
+type andNode struct {
+	nodes []exprNode
+}
+
+func (n *andNode) match(alert *models.Alert, matches int) bool {
+	isMatch := true
+	for _, node := range n.nodes {
+		if !node.match(alert, matches) {
+			isMatch = false
+		}
+	}
+	return isMatch
+}
+
+type orNode struct {
+	nodes []exprNode
+}
+
+func (n *orNode) match(alert *models.Alert, matches int) bool {
+	isMatch := false
+	for _, node := range n.nodes {
+		if node.match(alert, matches) {
+			isMatch = true
+		}
+	}
+	return isMatch
+}
+
+type notNode struct {
+	node exprNode
+}
+
+func (n *notNode) match(alert *models.Alert, matches int) bool {
+	return !n.node.match(alert, matches)
+}
+
+// exprParser is a recursive descent parser for filter expressions, "|" has
+// the lowest precedence, followed by implicit AND between terms separated by
+// whitespace and "!" which binds to the following term or group
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	terms  []FilterT
+}
+
+func (p *exprParser) peek() *exprToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []exprNode{node}
+	for t := p.peek(); t != nil && t.kind == tokenOr; t = p.peek() {
+		p.pos++
+		node, err = p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &orNode{nodes: nodes}, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	nodes := []exprNode{}
+	for t := p.peek(); t != nil && t.kind != tokenOr && t.kind != tokenClose; t = p.peek() {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, fmt.Errorf("missing filter at position %d", p.pos)
+	case 1:
+		return nodes[0], nil
+	}
+	return &andNode{nodes: nodes}, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	t := p.peek()
+	p.pos++
+	switch t.kind {
+	case tokenNot:
+		if p.peek() == nil {
+			return nil, fmt.Errorf("missing filter after '!'")
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: node}, nil
+	case tokenOpen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t == nil || t.kind != tokenClose {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return node, nil
+	case tokenTerm:
+		f := newSingleFilter(t.text)
+		if _, ok := f.(AlertLimitT); ok || isGroupFilter(f) {
+			return nil, fmt.Errorf("'%s' filter can't be used in expressions", f.GetName())
+		}
+		p.terms = append(p.terms, f)
+		return &termNode{filter: f}, nil
+	}
+	return nil, fmt.Errorf("unexpected '%s'", t.text)
+}
+
+// expressionFilter combines multiple filters using AND, OR and negation
+type expressionFilter struct {
+	alertFilter
+	root  exprNode
+	terms []FilterT
+}
+
+func (filter *expressionFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		isMatch := filter.root.match(alert, matches)
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func (filter *expressionFilter) GetTerms() []FilterT {
+	return filter.terms
+}
+
+func (filter *expressionFilter) setNow(now time.Time) {
+	filter.reference = now
+	for _, term := range filter.terms {
+		term.setNow(now)
+	}
+}
+
+// newExpressionFilter parses filter expression using given list of tokens,
+// expression is only valid if it can be parsed and all terms are valid
+func newExpressionFilter(expression string, tokens []exprToken) FilterT {
+	f := expressionFilter{}
+	f.init("", nil, expression, false, strings.TrimSpace(expression))
+
+	p := exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected '%s'", p.tokens[p.pos].text)
+	}
+	f.terms = p.terms
+	if err != nil {
+		f.setError(errorInvalidExpression, err.Error())
+		return &f
+	}
+
+	f.root = root
+	f.IsValid = true
+	for _, term := range f.terms {
+		if !term.GetIsValid() {
+			message := fmt.Sprintf("invalid filter '%s'", term.GetRawText())
+			if term.GetError() != nil {
+				message = fmt.Sprintf("%s: %s", message, term.GetError().Message)
+			}
+			f.setError(errorInvalidExpression, message)
+			break
+		}
+	}
+	return &f
+}
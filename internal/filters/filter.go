@@ -3,6 +3,8 @@ package filters
 import (
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/prymitive/karma/internal/models"
 	"github.com/prymitive/karma/internal/slices"
@@ -18,6 +20,33 @@ type FilterT interface {
 	GetName() string
 	GetMatcher() string
 	GetValue() string
+	GetTerms() []FilterT
+	GetError() *models.FilterError
+	setError(code, message string)
+	setNow(now time.Time)
+}
+
+// GroupFilterT is implemented by filters matching whole alert groups, their
+// Match() always returns true, MatchGroup() is called for every group once
+// all alert filters were applied
+type GroupFilterT interface {
+	FilterT
+	MatchGroup(group *models.AlertGroup) bool
+}
+
+// GroupLimitT is implemented by filters limiting the number of alerts
+// returned for each alert group
+type GroupLimitT interface {
+	FilterT
+	LimitGroup(group *models.AlertGroup)
+}
+
+// AlertLimitT is implemented by filters limiting the total number of alerts
+// returned, Match() is only called for alerts that passed all other alert and
+// group filters, alert groups and alerts are sorted before it's applied
+type AlertLimitT interface {
+	FilterT
+	GetLimit() int
 }
 
 type alertFilter struct {
@@ -28,6 +57,10 @@ type alertFilter struct {
 	Value   interface{}
 	IsValid bool
 	Hits    int
+	Error   *models.FilterError
+	// reference time used by filters comparing timestamps, if it's not set
+	// then the current time is used
+	reference time.Time
 }
 
 func (filter *alertFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
@@ -67,12 +100,64 @@ func (filter *alertFilter) GetValue() string {
 	return fmt.Sprintf("%s", filter.Value)
 }
 
+// GetTerms returns the list of filters this filter is combining, it's only
+// used by filters built from expressions using operators
+func (filter *alertFilter) GetTerms() []FilterT {
+	return nil
+}
+
+// GetError returns the reason why this filter is invalid, it's nil for valid
+// filters
+func (filter *alertFilter) GetError() *models.FilterError {
+	return filter.Error
+}
+
+// setError marks this filter as invalid and stores the reason
+func (filter *alertFilter) setError(code, message string) {
+	filter.IsValid = false
+	filter.Error = &models.FilterError{Code: code, Message: message}
+}
+
+func (filter *alertFilter) setNow(now time.Time) {
+	filter.reference = now
+}
+
+// now returns the time that relative filters like @age should be using
+func (filter *alertFilter) now() time.Time {
+	if filter.reference.IsZero() {
+		return time.Now()
+	}
+	return filter.reference
+}
+
 type newFilterFactory func() FilterT
 
 // NewFilter creates new filter object from filter expression like "key=value"
 // expression will be parsed and best filter implementation and value matcher
-// will be selected
+// will be selected.
+// Multiple filters can be combined into a single expression using "|" (OR),
+// "!" (negation) and parentheses, filters separated by whitespace are ANDed,
+// example: "(team=db | team=storage) !@state=suppressed"
+// Expressions are only used if every term is a "name op value" filter, so
+// plain text like "foo (bar)" is still a single filter.
 func NewFilter(expression string) FilterT {
+	tokens := tokenizeExpression(expression)
+	if isExpression(tokens) {
+		return newExpressionFilter(expression, tokens)
+	}
+	return newSingleFilter(expression)
+}
+
+// NewFilterAt works like NewFilter but all filters comparing timestamps will
+// be using passed time instead of the current time, zero time is ignored
+func NewFilterAt(expression string, now time.Time) FilterT {
+	f := NewFilter(expression)
+	f.setNow(now)
+	return f
+}
+
+// newSingleFilter creates new filter object from a single "key=value" filter
+func newSingleFilter(expression string) FilterT {
 	invalid := alwaysInvalidFilter{}
 	invalid.init("", nil, expression, false, expression)
 
@@ -96,6 +181,7 @@ func NewFilter(expression string) FilterT {
 		matcher, err := newMatcher(regexpOperator)
 		if err != nil {
 			f.init("", nil, expression, false, expression)
+			f.setError(errorUnknownOperator, err.Error())
 		} else {
 			f.init("", &matcher, expression, true, expression)
 		}
@@ -104,11 +190,7 @@ func NewFilter(expression string) FilterT {
 
 	if value == "" {
 		// there's no value, so it's always invalid
-		return &invalid
-	}
-
-	if operator == "" {
-		// no operator, no valid filter here
+		invalid.setError(errorMissingValue, fmt.Sprintf("missing value in '%s'", expression))
 		return &invalid
 	}
 
@@ -119,20 +201,24 @@ func NewFilter(expression string) FilterT {
 			// filter name doesn't match, keep searching
 			continue
 		}
+		matcher, err := newMatcher(operator)
+		if err != nil {
+			invalid.setError(errorUnknownOperator, fmt.Sprintf("unknown operator '%s', supported operators for %s filter: %s", operator, matched, strings.Join(fc.SupportedOperators, " ")))
+			return &invalid
+		}
 		if !slices.StringInSlice(fc.SupportedOperators, operator) {
+			invalid.setError(errorUnsupportedOperator, fmt.Sprintf("operator '%s' is not supported by %s filter, supported operators: %s", operator, matched, strings.Join(fc.SupportedOperators, " ")))
 			return &invalid
 		}
-		matcher, err := newMatcher(operator)
-		if err != nil {
-			f.init(matched, nil, expression, false, "")
-		} else {
-			if value != "" {
-				f.init(matched, &matcher, expression, true, value)
-				return f
-			}
-			f.init(matched, &matcher, expression, false, "")
+		if err = matcher.Validate(value); err != nil {
+			f.init(matched, &matcher, expression, false, value)
+			f.setError(errorInvalidRegex, fmt.Sprintf("invalid regular expression '%s': %s", value, err))
+			return f
 		}
+		f.init(matched, &matcher, expression, true, value)
+		return f
 	}
 
+	invalid.setError(errorUnknownFilter, fmt.Sprintf("unknown filter '%s'", matched))
 	return &invalid
 }
@@ -20,9 +20,9 @@ func (filter *ageFilter) init(name string, matcher *matcherT, rawText string, is
 	filter.RawText = rawText
 	filter.IsValid = isValid
 
-	dur, err := time.ParseDuration(value)
+	dur, err := parseDuration(value)
 	if err != nil {
-		filter.IsValid = false
+		filter.setError(errorInvalidValue, fmt.Sprintf("invalid duration '%s', expected value like 10m, 1h or 2d", value))
 	}
 	if dur > 0 {
 		filter.Value = -dur
@@ -33,7 +33,7 @@ func (filter *ageFilter) init(name string, matcher *matcherT, rawText string, is
 
 func (filter *ageFilter) Match(alert *models.Alert, matches int) bool {
 	if filter.IsValid {
-		ts := time.Now().Add(filter.Value.(time.Duration))
+		ts := filter.now().Add(filter.Value.(time.Duration))
 		isMatch := filter.Matcher.Compare(int(ts.Unix()), int(alert.StartsAt.Unix()))
 		if isMatch {
 			filter.Hits++
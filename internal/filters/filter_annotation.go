@@ -0,0 +1,72 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prymitive/karma/internal/models"
+)
+
+const annotationFilterPrefix = "@annotation."
+
+type annotationFilter struct {
+	alertFilter
+}
+
+func (filter *annotationFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		name := strings.TrimPrefix(filter.Matched, annotationFilterPrefix)
+		value := ""
+		for _, annotation := range alert.Annotations {
+			if annotation.Name == name {
+				value = annotation.Value
+				break
+			}
+		}
+		isMatch := filter.Matcher.Compare(value, filter.Value)
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newAnnotationFilter() FilterT {
+	f := annotationFilter{}
+	return &f
+}
+
+// annotationAutocomplete only generates hints for exact matches, annotation
+// values are usually long sentences so regex hints for every word would flood
+// the autocomplete
+func annotationAutocomplete(name string, operators []string, alerts []models.Alert) []models.Autocomplete {
+	tokens := map[string]models.Autocomplete{}
+	for _, alert := range alerts {
+		for _, annotation := range alert.Annotations {
+			key := annotationFilterPrefix + annotation.Name
+			for _, operator := range operators {
+				switch operator {
+				case equalOperator, notEqualOperator:
+					token := fmt.Sprintf("%s%s%s", key, operator, annotation.Value)
+					tokens[token] = makeAC(
+						token,
+						[]string{
+							key,
+							strings.TrimPrefix(key, "@"),
+							annotation.Name,
+							key + operator,
+							annotation.Value,
+						},
+					)
+				}
+			}
+		}
+	}
+	acData := []models.Autocomplete{}
+	for _, token := range tokens {
+		acData = append(acData, token)
+	}
+	return acData
+}
@@ -0,0 +1,65 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prymitive/karma/internal/models"
+)
+
+type clusterFilter struct {
+	alertFilter
+}
+
+func (filter *clusterFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		var isMatch bool
+		for _, am := range alert.Alertmanager {
+			if filter.Matcher.Compare(am.ClusterName, filter.Value) {
+				isMatch = true
+			}
+		}
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newClusterFilter() FilterT {
+	f := clusterFilter{}
+	return &f
+}
+
+func clusterAutocomplete(name string, operators []string, alerts []models.Alert) []models.Autocomplete {
+	tokens := map[string]models.Autocomplete{}
+	for _, alert := range alerts {
+		for _, am := range alert.Alertmanager {
+			if am.ClusterName == "" {
+				continue
+			}
+			for _, operator := range operators {
+				switch operator {
+				case equalOperator, notEqualOperator:
+					token := fmt.Sprintf("%s%s%s", name, operator, am.ClusterName)
+					tokens[token] = makeAC(
+						token,
+						[]string{
+							name,
+							strings.TrimPrefix(name, "@"),
+							name + operator,
+							am.ClusterName,
+						},
+					)
+				}
+			}
+		}
+	}
+	acData := []models.Autocomplete{}
+	for _, token := range tokens {
+		acData = append(acData, token)
+	}
+	return acData
+}
@@ -20,7 +20,7 @@ func (filter *fuzzyFilter) init(name string, matcher *matcherT, rawText string,
 	filter.IsValid = isValid
 	filter.Value = value
 	if _, err := regexp.Compile(value); err != nil {
-		filter.IsValid = false
+		filter.setError(errorInvalidRegex, fmt.Sprintf("invalid regular expression '%s': %s", value, err))
 	}
 }
 
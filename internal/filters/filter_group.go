@@ -0,0 +1,207 @@
+package filters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prymitive/karma/internal/models"
+	"github.com/prymitive/karma/internal/slices"
+)
+
+// isGroupFilter returns true if given filter is applied to alert groups
+// rather than individual alerts
+func isGroupFilter(filter FilterT) bool {
+	if _, ok := filter.(GroupFilterT); ok {
+		return true
+	}
+	if _, ok := filter.(GroupLimitT); ok {
+		return true
+	}
+	return false
+}
+
+// groupState returns the state of the alert group, group is active if any
+// alert is active, suppressed if all alerts are suppressed and unprocessed
+// otherwise
+func groupState(group *models.AlertGroup) string {
+	suppressed := 0
+	for _, alert := range group.Alerts {
+		switch alert.State {
+		case models.AlertStateActive:
+			return models.AlertStateActive
+		case models.AlertStateSuppressed:
+			suppressed++
+		}
+	}
+	if suppressed > 0 && suppressed == len(group.Alerts) {
+		return models.AlertStateSuppressed
+	}
+	return models.AlertStateUnprocessed
+}
+
+type groupSizeFilter struct {
+	alertFilter
+}
+
+func (filter *groupSizeFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
+	filter.Matched = name
+	if matcher != nil {
+		filter.Matcher = *matcher
+	}
+	filter.RawText = rawText
+	filter.IsValid = isValid
+	filter.Value = value
+	if filter.IsValid {
+		val, err := strconv.Atoi(value)
+		if err != nil || val < 0 {
+			filter.setError(errorInvalidValue, fmt.Sprintf("invalid group size '%s', expected a number", value))
+		} else {
+			filter.Value = val
+		}
+	}
+}
+
+func (filter *groupSizeFilter) GetValue() string {
+	return fmt.Sprintf("%v", filter.Value)
+}
+
+func (filter *groupSizeFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		return true
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func (filter *groupSizeFilter) MatchGroup(group *models.AlertGroup) bool {
+	if filter.IsValid {
+		isMatch := filter.Matcher.Compare(len(group.Alerts), filter.Value)
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("MatchGroup() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newGroupSizeFilter() FilterT {
+	f := groupSizeFilter{}
+	return &f
+}
+
+func groupSizeAutocomplete(name string, operators []string, alerts []models.Alert) []models.Autocomplete {
+	tokens := []models.Autocomplete{}
+	for _, operator := range operators {
+		if operator != moreThanOperator {
+			continue
+		}
+		for _, size := range []int{10, 50} {
+			tokens = append(tokens, makeAC(
+				fmt.Sprintf("%s%s%d", name, operator, size),
+				[]string{
+					name,
+					strings.TrimPrefix(name, "@"),
+					fmt.Sprintf("%s%s", name, operator),
+				},
+			))
+		}
+	}
+	return tokens
+}
+
+type groupStateFilter struct {
+	alertFilter
+}
+
+func (filter *groupStateFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
+	filter.Matched = name
+	if matcher != nil {
+		filter.Matcher = *matcher
+	}
+	filter.RawText = rawText
+	filter.IsValid = isValid
+	filter.Value = value
+	if !slices.StringInSlice(models.AlertStateList, value) {
+		filter.setError(errorInvalidValue, fmt.Sprintf("invalid state '%s', expected one of: %s", value, strings.Join(models.AlertStateList, " ")))
+	}
+}
+
+func (filter *groupStateFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		return true
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func (filter *groupStateFilter) MatchGroup(group *models.AlertGroup) bool {
+	if filter.IsValid {
+		isMatch := filter.Matcher.Compare(groupState(group), filter.Value)
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("MatchGroup() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newGroupStateFilter() FilterT {
+	f := groupStateFilter{}
+	return &f
+}
+
+type groupLimitFilter struct {
+	alertFilter
+}
+
+func (filter *groupLimitFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
+	filter.Matched = name
+	if matcher != nil {
+		filter.Matcher = *matcher
+	}
+	filter.RawText = rawText
+	filter.IsValid = isValid
+	filter.Value = value
+	if filter.IsValid {
+		val, err := strconv.Atoi(value)
+		if err != nil || val < 1 {
+			filter.setError(errorInvalidValue, fmt.Sprintf("invalid limit '%s', expected a positive number", value))
+		} else {
+			filter.Value = val
+		}
+	}
+}
+
+func (filter *groupLimitFilter) GetValue() string {
+	return fmt.Sprintf("%v", filter.Value)
+}
+
+func (filter *groupLimitFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		return true
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+// LimitGroup will remove all alerts above the limit from the group, hits are
+// counted for every group that was truncated
+func (filter *groupLimitFilter) LimitGroup(group *models.AlertGroup) {
+	if filter.IsValid {
+		if len(group.Alerts) > filter.Value.(int) {
+			group.Alerts = group.Alerts[:filter.Value.(int)]
+			filter.Hits++
+		}
+		return
+	}
+	e := fmt.Sprintf("LimitGroup() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newGroupLimitFilter() FilterT {
+	f := groupLimitFilter{}
+	return &f
+}
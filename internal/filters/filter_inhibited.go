@@ -0,0 +1,120 @@
+package filters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prymitive/karma/internal/models"
+)
+
+type inhibitedFilter struct {
+	alertFilter
+}
+
+func (filter *inhibitedFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
+	filter.Matched = name
+	if matcher != nil {
+		filter.Matcher = *matcher
+	}
+	filter.RawText = rawText
+	filter.IsValid = isValid
+	filter.Value = value
+	if value != "true" && value != "false" {
+		filter.setError(errorInvalidValue, fmt.Sprintf("invalid value '%s', expected true or false", value))
+	}
+}
+
+func (filter *inhibitedFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		isMatch := filter.Matcher.Compare(strconv.FormatBool(alert.IsInhibited()), filter.Value)
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newInhibitedFilter() FilterT {
+	f := inhibitedFilter{}
+	return &f
+}
+
+func inhibitedAutocomplete(name string, operators []string, alerts []models.Alert) []models.Autocomplete {
+	tokens := map[string]models.Autocomplete{}
+	for _, alert := range alerts {
+		for _, operator := range operators {
+			token := fmt.Sprintf("%s%s%s", name, operator, strconv.FormatBool(alert.IsInhibited()))
+			tokens[token] = makeAC(
+				token,
+				[]string{
+					name,
+					strings.TrimPrefix(name, "@"),
+					name + operator,
+				},
+			)
+		}
+	}
+	acData := []models.Autocomplete{}
+	for _, token := range tokens {
+		acData = append(acData, token)
+	}
+	return acData
+}
+
+type inhibitedByFilter struct {
+	alertFilter
+}
+
+func (filter *inhibitedByFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		var isMatch bool
+		if alert.IsInhibited() {
+			for _, fingerprint := range alert.InhibitedBy {
+				m := filter.Matcher.Compare(fingerprint, filter.Value)
+				if m {
+					isMatch = m
+				}
+			}
+		} else {
+			isMatch = filter.Matcher.Compare("", filter.Value)
+		}
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newInhibitedByFilter() FilterT {
+	f := inhibitedByFilter{}
+	return &f
+}
+
+func inhibitedByAutocomplete(name string, operators []string, alerts []models.Alert) []models.Autocomplete {
+	tokens := map[string]models.Autocomplete{}
+	for _, alert := range alerts {
+		if alert.IsInhibited() {
+			for _, fingerprint := range alert.InhibitedBy {
+				for _, operator := range operators {
+					token := fmt.Sprintf("%s%s%s", name, operator, fingerprint)
+					tokens[token] = makeAC(token, []string{
+						name,
+						strings.TrimPrefix(name, "@"),
+						fmt.Sprintf("%s%s", name, operator),
+						fingerprint,
+					})
+				}
+			}
+		}
+	}
+	acData := []models.Autocomplete{}
+	for _, token := range tokens {
+		acData = append(acData, token)
+	}
+	return acData
+}
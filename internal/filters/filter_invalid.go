@@ -7,4 +7,5 @@ type alwaysInvalidFilter struct {
 func (filter *alwaysInvalidFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
 	filter.Matched = name
 	filter.RawText = rawText
+	filter.Value = value
 }
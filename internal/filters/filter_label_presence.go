@@ -0,0 +1,79 @@
+package filters
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prymitive/karma/internal/models"
+)
+
+var labelNameRe = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+// labelPresenceFilter matches alerts based on the label being set or not,
+// it doesn't care about the label value, so an empty label is still present
+type labelPresenceFilter struct {
+	alertFilter
+	present bool
+}
+
+func (filter *labelPresenceFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
+	filter.Matched = name
+	if matcher != nil {
+		filter.Matcher = *matcher
+	}
+	filter.RawText = rawText
+	filter.IsValid = isValid
+	filter.Value = value
+	if !labelNameRe.MatchString(value) {
+		filter.setError(errorInvalidValue, fmt.Sprintf("invalid label name '%s'", value))
+	}
+}
+
+func (filter *labelPresenceFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		_, found := alert.Labels[filter.Value.(string)]
+		isMatch := found == filter.present
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newHasLabelFilter() FilterT {
+	f := labelPresenceFilter{present: true}
+	return &f
+}
+
+func newMissingLabelFilter() FilterT {
+	f := labelPresenceFilter{present: false}
+	return &f
+}
+
+func labelPresenceAutocomplete(name string, operators []string, alerts []models.Alert) []models.Autocomplete {
+	tokens := map[string]models.Autocomplete{}
+	for _, alert := range alerts {
+		for key := range alert.Labels {
+			for _, operator := range operators {
+				token := fmt.Sprintf("%s%s%s", name, operator, key)
+				tokens[token] = makeAC(
+					token,
+					[]string{
+						name,
+						strings.TrimPrefix(name, "@"),
+						name + operator,
+						key,
+					},
+				)
+			}
+		}
+	}
+	acData := []models.Autocomplete{}
+	for _, token := range tokens {
+		acData = append(acData, token)
+	}
+	return acData
+}
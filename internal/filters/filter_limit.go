@@ -22,7 +22,7 @@ func (filter *limitFilter) init(name string, matcher *matcherT, rawText string,
 	if filter.IsValid {
 		val, err := strconv.Atoi(value)
 		if err != nil || val < 1 {
-			filter.IsValid = false
+			filter.setError(errorInvalidValue, fmt.Sprintf("invalid limit '%s', expected a positive number", value))
 		} else {
 			filter.Value = val
 		}
@@ -41,6 +41,13 @@ func (filter *limitFilter) Match(alert *models.Alert, matches int) bool {
 	panic(e)
 }
 
+func (filter *limitFilter) GetLimit() int {
+	if limit, ok := filter.Value.(int); ok {
+		return limit
+	}
+	return 0
+}
+
 func newLimitFilter() FilterT {
 	f := limitFilter{}
 	return &f
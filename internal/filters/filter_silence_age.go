@@ -0,0 +1,57 @@
+package filters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prymitive/karma/internal/models"
+)
+
+type silenceAgeFilter struct {
+	alertFilter
+}
+
+func (filter *silenceAgeFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
+	filter.Matched = name
+	if matcher != nil {
+		filter.Matcher = *matcher
+	}
+	filter.RawText = rawText
+	filter.IsValid = isValid
+
+	dur, err := parseDuration(value)
+	if err != nil || dur < 0 {
+		filter.setError(errorInvalidValue, fmt.Sprintf("invalid duration '%s', expected positive value like 10m, 1h or 2d", value))
+	}
+	filter.Value = dur
+}
+
+// Match uses silence startsAt, not all Alertmanager versions tell us when
+// the silence was created
+func (filter *silenceAgeFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		var isMatch bool
+		if alert.IsSilenced() {
+			ts := filter.now().Add(-filter.Value.(time.Duration))
+			for _, silenceID := range alert.SilencedBy {
+				for _, am := range alert.Alertmanager {
+					silence, found := am.Silences[silenceID]
+					if found && filter.Matcher.Compare(int(ts.Unix()), int(silence.StartsAt.Unix())) {
+						isMatch = true
+					}
+				}
+			}
+		}
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newSilenceAgeFilter() FilterT {
+	f := silenceAgeFilter{}
+	return &f
+}
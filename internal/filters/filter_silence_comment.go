@@ -0,0 +1,43 @@
+package filters
+
+import (
+	"fmt"
+
+	"github.com/prymitive/karma/internal/models"
+)
+
+type silenceCommentFilter struct {
+	alertFilter
+}
+
+func (filter *silenceCommentFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		var isMatch bool
+		if alert.IsSilenced() {
+			for _, silenceID := range alert.SilencedBy {
+				for _, am := range alert.Alertmanager {
+					silence, found := am.Silences[silenceID]
+					if found {
+						m := filter.Matcher.Compare(silence.Comment, filter.Value)
+						if m {
+							isMatch = m
+						}
+					}
+				}
+			}
+		} else {
+			isMatch = filter.Matcher.Compare("", filter.Value)
+		}
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newSilenceCommentFilter() FilterT {
+	f := silenceCommentFilter{}
+	return &f
+}
@@ -0,0 +1,81 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prymitive/karma/internal/models"
+)
+
+type silenceExpiresFilter struct {
+	alertFilter
+}
+
+func (filter *silenceExpiresFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
+	filter.Matched = name
+	if matcher != nil {
+		filter.Matcher = *matcher
+	}
+	filter.RawText = rawText
+	filter.IsValid = isValid
+
+	dur, err := parseDuration(value)
+	if err != nil || dur < 0 {
+		filter.setError(errorInvalidValue, fmt.Sprintf("invalid duration '%s', expected positive value like 10m, 1h or 2d", value))
+	}
+	filter.Value = dur
+}
+
+func (filter *silenceExpiresFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		var isMatch bool
+		if alert.IsSilenced() {
+			ts := filter.now().Add(filter.Value.(time.Duration))
+			for _, silenceID := range alert.SilencedBy {
+				for _, am := range alert.Alertmanager {
+					silence, found := am.Silences[silenceID]
+					if found && filter.Matcher.Compare(int(silence.EndsAt.Unix()), int(ts.Unix())) {
+						isMatch = true
+					}
+				}
+			}
+		}
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newSilenceExpiresFilter() FilterT {
+	f := silenceExpiresFilter{}
+	return &f
+}
+
+// silenceDurationAutocomplete returns a static set of hints for filters using
+// silence timestamps, but only if there are any silenced alerts
+func silenceDurationAutocomplete(name string, operators []string, alerts []models.Alert) []models.Autocomplete {
+	tokens := []models.Autocomplete{}
+	for _, alert := range alerts {
+		if !alert.IsSilenced() {
+			continue
+		}
+		for _, operator := range operators {
+			for _, value := range []string{"1h", "1d"} {
+				tokens = append(tokens, makeAC(
+					fmt.Sprintf("%s%s%s", name, operator, value),
+					[]string{
+						name,
+						strings.TrimPrefix(name, "@"),
+						fmt.Sprintf("%s%s", name, operator),
+					},
+				))
+			}
+		}
+		break
+	}
+	return tokens
+}
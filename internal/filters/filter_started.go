@@ -0,0 +1,75 @@
+package filters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prymitive/karma/internal/models"
+)
+
+// timestampLayouts lists all formats accepted by ParseTimestamp, seconds can
+// be omitted since those are rarely needed when looking at alert history
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+}
+
+// ParseTimestamp parses RFC3339 timestamps, it also accepts timestamps
+// without seconds, like 2019-05-01T10:00Z
+func ParseTimestamp(value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if ts, err := time.Parse(layout, value); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp '%s', expected RFC3339 value like 2019-05-01T10:00:00Z", value)
+}
+
+type startedFilter struct {
+	alertFilter
+}
+
+func (filter *startedFilter) init(name string, matcher *matcherT, rawText string, isValid bool, value string) {
+	filter.Matched = name
+	if matcher != nil {
+		filter.Matcher = *matcher
+	}
+	filter.RawText = rawText
+	filter.IsValid = isValid
+
+	ts, err := ParseTimestamp(value)
+	if err != nil {
+		filter.setError(errorInvalidValue, err.Error())
+	}
+	// keep the raw value for invalid filters, so it's returned as is in the
+	// API response
+	if !filter.IsValid {
+		filter.Value = value
+		return
+	}
+	filter.Value = ts
+}
+
+func (filter *startedFilter) GetValue() string {
+	if !filter.IsValid {
+		return fmt.Sprintf("%v", filter.Value)
+	}
+	return filter.Value.(time.Time).Format(time.RFC3339)
+}
+
+func (filter *startedFilter) Match(alert *models.Alert, matches int) bool {
+	if filter.IsValid {
+		isMatch := filter.Matcher.Compare(int(alert.StartsAt.Unix()), int(filter.Value.(time.Time).Unix()))
+		if isMatch {
+			filter.Hits++
+		}
+		return isMatch
+	}
+	e := fmt.Sprintf("Match() called on invalid filter %#v", filter)
+	panic(e)
+}
+
+func newStartedFilter() FilterT {
+	f := startedFilter{}
+	return &f
+}
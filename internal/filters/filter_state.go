@@ -21,7 +21,7 @@ func (filter *stateFilter) init(name string, matcher *matcherT, rawText string,
 	filter.IsValid = isValid
 	filter.Value = value
 	if !slices.StringInSlice(models.AlertStateList, value) {
-		filter.IsValid = false
+		filter.setError(errorInvalidValue, fmt.Sprintf("invalid state '%s', expected one of: %s", value, strings.Join(models.AlertStateList, " ")))
 	}
 }
 
@@ -272,6 +272,133 @@ var tests = []filterTest{
 		Silence:    models.Silence{ID: "1"},
 		IsMatch:    false,
 	},
+	{
+		Expression: "@silence_comment=~maintenance",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", Comment: "Planned maintenance window"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@silence_comment=~upgrade",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", Comment: "Planned maintenance window"},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_comment!~maintenance",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", Comment: "Planned maintenance window"},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_comment=Planned maintenance window",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", Comment: "Planned maintenance window"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@silence_comment=~maintenance",
+		IsValid:    true,
+		Alert:      models.Alert{State: "active"},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_comment<foo",
+		IsValid:    false,
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_expires<1h",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", EndsAt: time.Now().Add(time.Minute * 30)},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@silence_expires<1h",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", EndsAt: time.Now().Add(time.Hour * 2)},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_expires>1d",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", EndsAt: time.Now().Add(time.Hour * 48)},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@silence_expires>1d",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", EndsAt: time.Now().Add(time.Hour * 2)},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_expires<1h",
+		IsValid:    true,
+		Alert:      models.Alert{State: "active"},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_expires>-1h",
+		IsValid:    false,
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_expires=1h",
+		IsValid:    false,
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_expires<1x",
+		IsValid:    false,
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_age>1h",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", StartsAt: time.Now().Add(time.Hour * -2)},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@silence_age>1h",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", StartsAt: time.Now().Add(time.Minute * -30)},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_age<1d",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", StartsAt: time.Now().Add(time.Hour * -2)},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@silence_age<1d",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		Silence:    models.Silence{ID: "1", StartsAt: time.Now().Add(time.Hour * -48)},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_age>1h",
+		IsValid:    true,
+		Alert:      models.Alert{State: "active"},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@silence_age=1h",
+		IsValid:    false,
+		IsMatch:    false,
+	},
 
 	{
 		Expression: "@age<1h",
@@ -297,6 +424,54 @@ var tests = []filterTest{
 		Alert:      models.Alert{StartsAt: time.Now().Add(time.Hour * -2)},
 		IsMatch:    true,
 	},
+	{
+		Expression: "@age>1d",
+		IsValid:    true,
+		Alert:      models.Alert{StartsAt: time.Now().Add(time.Hour * -25)},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@age>1d",
+		IsValid:    true,
+		Alert:      models.Alert{StartsAt: time.Now().Add(time.Hour * -23)},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@started>2019-05-01T10:00Z",
+		IsValid:    true,
+		Alert:      models.Alert{StartsAt: time.Date(2019, 5, 1, 10, 15, 0, 0, time.UTC)},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@started>2019-05-01T10:00:00Z",
+		IsValid:    true,
+		Alert:      models.Alert{StartsAt: time.Date(2019, 5, 1, 9, 45, 0, 0, time.UTC)},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@started<2019-05-01T12:30+02:00",
+		IsValid:    true,
+		Alert:      models.Alert{StartsAt: time.Date(2019, 5, 1, 10, 15, 0, 0, time.UTC)},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@started<2019-05-01T10:00Z",
+		IsValid:    true,
+		Alert:      models.Alert{StartsAt: time.Date(2019, 5, 1, 10, 15, 0, 0, time.UTC)},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@started=2019-05-01T10:00Z",
+		IsValid:    false,
+	},
+	{
+		Expression: "@started>2019-05-01",
+		IsValid:    false,
+	},
+	{
+		Expression: "@started>1h",
+		IsValid:    false,
+	},
 	{
 		Expression: "@age=1h",
 		IsValid:    false,
@@ -364,6 +539,52 @@ var tests = []filterTest{
 		Alert:      models.Alert{Labels: map[string]string{"node": "vps1"}},
 		IsMatch:    true,
 	},
+	{
+		Expression: "node==~vps",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"node": "vps1"}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "node==~vps[0-9]",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"node": "vps1"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "node==~VPS[0-9]",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"node": "vps1"}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "node!=~vps",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"node": "vps1"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "node!=~vps[0-9]",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"node": "vps1"}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@receiver==~by-.+",
+		IsValid:    true,
+		Alert:      models.Alert{Receiver: "by-name"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@receiver==~name",
+		IsValid:    true,
+		Alert:      models.Alert{Receiver: "by-name"},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@state==~active",
+		IsValid:    false,
+	},
 	{
 		Expression: "node!~vps",
 		IsValid:    true,
@@ -394,6 +615,64 @@ var tests = []filterTest{
 		Alert:      models.Alert{Labels: map[string]string{"node": "vps1"}},
 		IsMatch:    false,
 	},
+	{
+		Expression: "version>1.10",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"version": "1.9.2"}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "version>1.10",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"version": "1.10.1"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "version>=1.10",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"version": "1.10.0"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "version<=v2.0.0",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"version": "v2.0.0-rc.1"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "version<1.10",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"version": "v1.9"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "threshold>0.9",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"threshold": "0.95"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "threshold>=0.95",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"threshold": "0.95"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "threshold<=0.9",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{"threshold": "0.95"}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "threshold<0.9",
+		IsValid:    true,
+		Alert:      models.Alert{Labels: map[string]string{}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "node=>1",
+		IsValid:    false,
+	},
 
 	{
 		Expression: "abc",
@@ -495,82 +774,298 @@ var tests = []filterTest{
 	},
 	{
 		Expression: "@inhibited=true",
-		IsValid:    false,
-	},
-	{
-		Expression: "@inhibited!=false",
-		IsValid:    false,
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", InhibitedBy: []string{"1"}},
+		IsMatch:    true,
 	},
 	{
-		Expression: "@inhibited=~false",
-		IsValid:    false,
+		Expression: "@inhibited=true",
+		IsValid:    true,
+		Alert:      models.Alert{State: "active", InhibitedBy: []string{"1"}},
+		IsMatch:    false,
 	},
 	{
-		Expression: "@alertmanager=test",
+		Expression: "@inhibited=true",
 		IsValid:    true,
-		Alert:      models.Alert{},
-		IsMatch:    true,
+		Alert:      models.Alert{State: "suppressed", SilencedBy: []string{"1"}},
+		IsMatch:    false,
 	},
 	{
-		Expression: "@alertmanager=abc",
+		Expression: "@inhibited=false",
 		IsValid:    true,
-		Alert:      models.Alert{},
-		IsMatch:    false,
+		Alert:      models.Alert{State: "active"},
+		IsMatch:    true,
 	},
 	{
-		Expression: "@alertmanager=~tes",
+		Expression: "@inhibited!=false",
 		IsValid:    true,
-		Alert:      models.Alert{},
+		Alert:      models.Alert{State: "suppressed", InhibitedBy: []string{"1"}},
 		IsMatch:    true,
 	},
 	{
-		Expression: "@alertmanager=~000",
+		Expression: "@inhibited!=true",
 		IsValid:    true,
-		Alert:      models.Alert{},
+		Alert:      models.Alert{State: "suppressed", InhibitedBy: []string{"1"}},
 		IsMatch:    false,
 	},
 	{
-		Expression: "@alertmanager!=tes",
+		Expression: "@inhibited=yes",
+		IsValid:    false,
+	},
+	{
+		Expression: "@has=team",
 		IsValid:    true,
-		Alert:      models.Alert{},
+		Alert:      models.Alert{Labels: map[string]string{"team": "db"}},
 		IsMatch:    true,
 	},
 	{
-		Expression: "@alertmanager!~abc",
+		Expression: "@has=team",
 		IsValid:    true,
-		Alert:      models.Alert{},
+		Alert:      models.Alert{Labels: map[string]string{"team": ""}},
 		IsMatch:    true,
 	},
 	{
-		Expression: "@receiver=by-name",
+		Expression: "@has=team",
 		IsValid:    true,
-		Alert: models.Alert{
-			Receiver: "by-name",
-		},
-		IsMatch: true,
+		Alert:      models.Alert{Labels: map[string]string{"job": "db"}},
+		IsMatch:    false,
 	},
 	{
-		Expression: "@receiver=by-name",
+		Expression: "@missing=team",
 		IsValid:    true,
-		Alert: models.Alert{
-			Receiver: "by-not-name",
-		},
-		IsMatch: false,
+		Alert:      models.Alert{Labels: map[string]string{"job": "db"}},
+		IsMatch:    true,
 	},
 	{
-		Expression: "@receiver=~name",
+		Expression: "@missing=team",
 		IsValid:    true,
-		Alert: models.Alert{
-			Receiver: "by-not-name",
-		},
-		IsMatch: true,
+		Alert:      models.Alert{Labels: map[string]string{"team": ""}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@missing=team",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@has!=team",
+		IsValid:    false,
+	},
+	{
+		Expression: "@has=~team",
+		IsValid:    false,
+	},
+	{
+		Expression: "@missing=1team",
+		IsValid:    false,
+	},
+	{
+		Expression: "@inhibited=~false",
+		IsValid:    false,
+	},
+	{
+		Expression: "@inhibited_by=abcdef",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", InhibitedBy: []string{"1", "abcdef"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@inhibited_by=abcdef",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", InhibitedBy: []string{"1"}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@inhibited_by=abcdef",
+		IsValid:    true,
+		Alert:      models.Alert{State: "active", InhibitedBy: []string{"abcdef"}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@inhibited_by!=abcdef",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", InhibitedBy: []string{"abcdef"}},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@inhibited_by!=abcdef",
+		IsValid:    true,
+		Alert:      models.Alert{State: "suppressed", InhibitedBy: []string{"1"}},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@inhibited_by=~abc",
+		IsValid:    false,
+	},
+	{
+		Expression: "@alertmanager=test",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@alertmanager=abc",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@alertmanager=~tes",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@alertmanager=~000",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@alertmanager!=tes",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@alertmanager!~abc",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@cluster=ha",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@cluster=test",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@cluster=~h",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@cluster!=ha",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@cluster!~abc",
+		IsValid:    true,
+		Alert:      models.Alert{},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@cluster>ha",
+		IsValid:    false,
+	},
+	{
+		Expression: "@annotation.summary=foo bar",
+		IsValid:    true,
+		Alert: models.Alert{
+			Annotations: models.Annotations{{Name: "summary", Value: "foo bar"}},
+		},
+		IsMatch: true,
+	},
+	{
+		Expression: "@annotation.summary=foo",
+		IsValid:    true,
+		Alert: models.Alert{
+			Annotations: models.Annotations{{Name: "summary", Value: "foo bar"}},
+		},
+		IsMatch: false,
+	},
+	{
+		Expression: "@annotation.summary!=foo",
+		IsValid:    true,
+		Alert: models.Alert{
+			Annotations: models.Annotations{{Name: "summary", Value: "foo bar"}},
+		},
+		IsMatch: true,
+	},
+	{
+		Expression: "@annotation.owner!=team-a",
+		IsValid:    true,
+		Alert: models.Alert{
+			Annotations: models.Annotations{{Name: "summary", Value: "foo bar"}},
+		},
+		IsMatch: true,
+	},
+	{
+		Expression: "@annotation.dashboard=~grafana",
+		IsValid:    true,
+		Alert: models.Alert{
+			Annotations: models.Annotations{{Name: "dashboard", Value: "https://grafana.example.com"}},
+		},
+		IsMatch: true,
+	},
+	{
+		Expression: "@annotation.dashboard!~grafana",
+		IsValid:    true,
+		Alert: models.Alert{
+			Annotations: models.Annotations{{Name: "dashboard", Value: "https://grafana.example.com"}},
+		},
+		IsMatch: false,
+	},
+	{
+		Expression: "@annotation.summary=~foo",
+		IsValid:    true,
+		Alert: models.Alert{
+			Labels: map[string]string{"summary": "foo"},
+		},
+		IsMatch: false,
+	},
+	{
+		Expression: "@annotation.summary>1",
+		IsValid:    false,
+	},
+	{
+		Expression: "@annotation=foo",
+		IsValid:    false,
+	},
+	{
+		Expression: "summary.foo=bar",
+		IsValid:    false,
+	},
+	{
+		Expression: "@receiver=by-name",
+		IsValid:    true,
+		Alert: models.Alert{
+			Receiver: "by-name",
+		},
+		IsMatch: true,
+	},
+	{
+		Expression: "@receiver=by-name",
+		IsValid:    true,
+		Alert: models.Alert{
+			Receiver: "by-not-name",
+		},
+		IsMatch: false,
+	},
+	{
+		Expression: "@receiver=~name",
+		IsValid:    true,
+		Alert: models.Alert{
+			Receiver: "by-not-name",
+		},
+		IsMatch: true,
 	},
 }
 
 func TestFilters(t *testing.T) {
 	log.SetLevel(log.ErrorLevel)
 
-	am, err := alertmanager.NewAlertmanager("test", "http://localhost", alertmanager.WithRequestTimeout(time.Second))
+	am, err := alertmanager.NewAlertmanager("test", "http://localhost", alertmanager.WithRequestTimeout(time.Second), alertmanager.WithCluster("ha"))
 	if err != nil {
 		t.Error(err)
 	}
@@ -578,9 +1073,10 @@ func TestFilters(t *testing.T) {
 		alert := models.Alert(ft.Alert)
 		alert.Alertmanager = []models.AlertmanagerInstance{
 			{
-				Name:       am.Name,
-				Silences:   map[string]*models.Silence{},
-				SilencedBy: []string{},
+				Name:        am.Name,
+				ClusterName: am.ClusterName(),
+				Silences:    map[string]*models.Silence{},
+				SilencedBy:  []string{},
 			},
 		}
 		if ft.Silence.ID != "" {
@@ -598,6 +1094,9 @@ func TestFilters(t *testing.T) {
 		if f.GetIsValid() != ft.IsValid {
 			t.Errorf("[%s] GetIsValid() returned %#v while %#v was expected", ft.Expression, f.GetIsValid(), ft.IsValid)
 		}
+		if f.GetIsValid() != (f.GetError() == nil) {
+			t.Errorf("[%s] GetError() returned %#v for filter with GetIsValid()=%#v", ft.Expression, f.GetError(), f.GetIsValid())
+		}
 		if f.GetIsValid() {
 			m := f.Match(&alert, 0)
 			if m != ft.IsMatch {
@@ -618,6 +1117,296 @@ func TestFilters(t *testing.T) {
 	}
 }
 
+type filterErrorTest struct {
+	Expression string
+	Code       string
+	Message    string
+}
+
+var filterErrorTests = []filterErrorTest{
+	{
+		Expression: "foo=",
+		Code:       "missing_value",
+		Message:    "missing value in 'foo='",
+	},
+	{
+		Expression: "@foo=bar",
+		Code:       "unknown_filter",
+		Message:    "unknown filter '@foo'",
+	},
+	{
+		Expression: "foo===bar",
+		Code:       "unknown_operator",
+	},
+	{
+		Expression: "@age=1h",
+		Code:       "unsupported_operator",
+		Message:    "operator '=' is not supported by @age filter, supported operators: < > <= >=",
+	},
+	{
+		Expression: "foo=~(",
+		Code:       "invalid_regex",
+	},
+	{
+		Expression: "foo==~(",
+		Code:       "invalid_regex",
+	},
+	{
+		Expression: "abc(",
+		Code:       "invalid_regex",
+	},
+	{
+		Expression: "@age>1x",
+		Code:       "invalid_value",
+		Message:    "invalid duration '1x', expected value like 10m, 1h or 2d",
+	},
+	{
+		Expression: "@silence_expires<-1h",
+		Code:       "invalid_value",
+	},
+	{
+		Expression: "@state=foo",
+		Code:       "invalid_value",
+		Message:    "invalid state 'foo', expected one of: unprocessed active suppressed",
+	},
+	{
+		Expression: "@limit=0",
+		Code:       "invalid_value",
+	},
+	{
+		Expression: "@inhibited=yes",
+		Code:       "invalid_value",
+	},
+	{
+		Expression: "(foo=bar",
+		Code:       "invalid_expression",
+		Message:    "missing closing parenthesis",
+	},
+	{
+		Expression: "foo=bar | @group_size>1",
+		Code:       "invalid_expression",
+		Message:    "'@group_size' filter can't be used in expressions",
+	},
+	{
+		Expression: "foo=bar | @limit=5",
+		Code:       "invalid_expression",
+		Message:    "'@limit' filter can't be used in expressions",
+	},
+	{
+		Expression: "@group_size>many",
+		Code:       "invalid_value",
+	},
+	{
+		Expression: "@group_state=firing",
+		Code:       "invalid_value",
+	},
+	{
+		Expression: "@group_limit=0",
+		Code:       "invalid_value",
+	},
+	{
+		Expression: "@group_limit>1",
+		Code:       "unsupported_operator",
+	},
+	{
+		Expression: "foo=bar | @age=1h",
+		Code:       "invalid_expression",
+		Message:    "invalid filter '@age=1h': operator '=' is not supported by @age filter, supported operators: < > <= >=",
+	},
+}
+
+func TestFilterErrors(t *testing.T) {
+	for _, ft := range filterErrorTests {
+		f := filters.NewFilter(ft.Expression)
+		if f.GetIsValid() {
+			t.Errorf("[%s] GetIsValid() returned true", ft.Expression)
+			continue
+		}
+		if f.GetError() == nil {
+			t.Errorf("[%s] GetError() returned nil", ft.Expression)
+			continue
+		}
+		if f.GetError().Code != ft.Code {
+			t.Errorf("[%s] GetError() returned code '%s' while '%s' was expected", ft.Expression, f.GetError().Code, ft.Code)
+		}
+		if ft.Message != "" && f.GetError().Message != ft.Message {
+			t.Errorf("[%s] GetError() returned message '%s' while '%s' was expected", ft.Expression, f.GetError().Message, ft.Message)
+		}
+	}
+}
+
+type filterAtTest struct {
+	Expression string
+	Now        time.Time
+	StartsAt   time.Time
+	IsMatch    bool
+}
+
+var filterAtTests = []filterAtTest{
+	{
+		Expression: "@age>1h",
+		Now:        time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC),
+		StartsAt:   time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC),
+		IsMatch:    true,
+	},
+	{
+		Expression: "@age>1h",
+		Now:        time.Date(2019, 5, 1, 10, 30, 0, 0, time.UTC),
+		StartsAt:   time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC),
+		IsMatch:    false,
+	},
+	{
+		Expression: "@age<1h | foo=bar",
+		Now:        time.Date(2019, 5, 1, 10, 30, 0, 0, time.UTC),
+		StartsAt:   time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC),
+		IsMatch:    true,
+	},
+	{
+		Expression: "@age<1h",
+		StartsAt:   time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC),
+		IsMatch:    false,
+	},
+}
+
+func TestNewFilterAt(t *testing.T) {
+	for _, ft := range filterAtTests {
+		f := filters.NewFilterAt(ft.Expression, ft.Now)
+		if !f.GetIsValid() {
+			t.Errorf("[%s] GetIsValid() returned false", ft.Expression)
+			continue
+		}
+		alert := models.Alert{StartsAt: ft.StartsAt}
+		if m := f.Match(&alert, 0); m != ft.IsMatch {
+			t.Errorf("[%s] Match() at %s returned %#v while %#v was expected", ft.Expression, ft.Now, m, ft.IsMatch)
+		}
+	}
+}
+
+func TestStartedFilterValue(t *testing.T) {
+	for expression, value := range map[string]string{
+		"@started>2019-05-01T10:00Z":       "2019-05-01T10:00:00Z",
+		"@started<2019-05-01T12:30+02:00":  "2019-05-01T12:30:00+02:00",
+		"@started>2019-05-01":              "2019-05-01",
+		"@started>1h":                      "1h",
+		"@started=2019-05-01T10:00Z":       "@started=2019-05-01T10:00Z",
+		"@started>2019-05-01T10:00:00Zabc": "2019-05-01T10:00:00Zabc",
+	} {
+		f := filters.NewFilter(expression)
+		if f.GetValue() != value {
+			t.Errorf("[%s] GetValue() returned %q, expected %q", expression, f.GetValue(), value)
+		}
+	}
+}
+
+type groupFilterTest struct {
+	Expression string
+	States     []string
+	IsMatch    bool
+	Alerts     int
+}
+
+var groupFilterTests = []groupFilterTest{
+	{
+		Expression: "@group_size>2",
+		States:     []string{"active", "active", "active"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@group_size>2",
+		States:     []string{"active", "active"},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@group_size<=2",
+		States:     []string{"active", "active"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@group_size=1",
+		States:     []string{"suppressed"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@group_state=active",
+		States:     []string{"suppressed", "active"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@group_state=suppressed",
+		States:     []string{"suppressed", "active"},
+		IsMatch:    false,
+	},
+	{
+		Expression: "@group_state=suppressed",
+		States:     []string{"suppressed", "suppressed"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@group_state=unprocessed",
+		States:     []string{"suppressed", "unprocessed"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@group_state!=active",
+		States:     []string{"suppressed", "unprocessed"},
+		IsMatch:    true,
+	},
+	{
+		Expression: "@group_limit=2",
+		States:     []string{"active", "active", "active"},
+		IsMatch:    true,
+		Alerts:     2,
+	},
+	{
+		Expression: "@group_limit=5",
+		States:     []string{"active", "active", "active"},
+		IsMatch:    false,
+		Alerts:     3,
+	},
+}
+
+func TestGroupFilters(t *testing.T) {
+	for _, ft := range groupFilterTests {
+		group := models.AlertGroup{Alerts: models.AlertList{}}
+		for _, state := range ft.States {
+			group.Alerts = append(group.Alerts, models.Alert{State: state})
+		}
+
+		f := filters.NewFilter(ft.Expression)
+		if !f.GetIsValid() {
+			t.Errorf("[%s] GetIsValid() returned false", ft.Expression)
+			continue
+		}
+		for _, alert := range group.Alerts {
+			alert := alert // scopelint pin
+			if !f.Match(&alert, 0) {
+				t.Errorf("[%s] Match() returned false", ft.Expression)
+			}
+		}
+
+		switch gf := f.(type) {
+		case filters.GroupFilterT:
+			if m := gf.MatchGroup(&group); m != ft.IsMatch {
+				t.Errorf("[%s] MatchGroup() returned %#v while %#v was expected", ft.Expression, m, ft.IsMatch)
+			}
+		case filters.GroupLimitT:
+			gf.LimitGroup(&group)
+			if len(group.Alerts) != ft.Alerts {
+				t.Errorf("[%s] LimitGroup() left %d alert(s) while %d was expected", ft.Expression, len(group.Alerts), ft.Alerts)
+			}
+		default:
+			t.Errorf("[%s] Filter isn't a group filter", ft.Expression)
+			continue
+		}
+		if ft.IsMatch && f.GetHits() != 1 {
+			t.Errorf("[%s] GetHits() returned %#v after match, expected 1", ft.Expression, f.GetHits())
+		}
+		if !ft.IsMatch && f.GetHits() != 0 {
+			t.Errorf("[%s] GetHits() returned %#v after non-match, expected 0", ft.Expression, f.GetHits())
+		}
+	}
+}
+
 type limitFilterTest struct {
 	Expression string
 	IsValid    bool
@@ -697,3 +1486,174 @@ func TestLimitFilter(t *testing.T) {
 		}
 	}
 }
+
+type expressionFilterTest struct {
+	Expression string
+	IsValid    bool
+	IsMatch    bool
+	Terms      []string
+	TermHits   []int
+}
+
+var expressionAlert = models.Alert{
+	State:    models.AlertStateSuppressed,
+	Receiver: "by-name",
+	Labels:   map[string]string{"team": "db", "job": "node", "severity": "critical"},
+}
+
+var expressionTests = []expressionFilterTest{
+	{
+		Expression: "team=db | team=storage",
+		IsValid:    true,
+		IsMatch:    true,
+		Terms:      []string{"team=db", "team=storage"},
+		TermHits:   []int{1, 0},
+	},
+	{
+		Expression: "(team=db | team=storage) !@state=suppressed",
+		IsValid:    true,
+		IsMatch:    false,
+		Terms:      []string{"team=db", "team=storage", "@state=suppressed"},
+		TermHits:   []int{1, 0, 1},
+	},
+	{
+		Expression: "(team=db | team=storage) !@state=active",
+		IsValid:    true,
+		IsMatch:    true,
+		Terms:      []string{"team=db", "team=storage", "@state=active"},
+		TermHits:   []int{1, 0, 0},
+	},
+	{
+		Expression: "!(team=db severity=critical)",
+		IsValid:    true,
+		IsMatch:    false,
+		Terms:      []string{"team=db", "severity=critical"},
+		TermHits:   []int{1, 1},
+	},
+	{
+		Expression: "(job=~(node|mysql)) | team=storage",
+		IsValid:    true,
+		IsMatch:    true,
+		Terms:      []string{"job=~(node|mysql)", "team=storage"},
+		TermHits:   []int{1, 0},
+	},
+	{
+		Expression: "!@receiver=by-cluster",
+		IsValid:    true,
+		IsMatch:    true,
+		Terms:      []string{"@receiver=by-cluster"},
+		TermHits:   []int{0},
+	},
+	{
+		Expression: "(team=db",
+		IsValid:    false,
+		Terms:      []string{"team=db"},
+	},
+	{
+		Expression: "team=db |",
+		IsValid:    false,
+		Terms:      []string{"team=db"},
+	},
+	{
+		Expression: "()",
+		IsValid:    false,
+		Terms:      []string{},
+	},
+	{
+		Expression: "team=db | @state=xx",
+		IsValid:    false,
+		Terms:      []string{"team=db", "@state=xx"},
+	},
+	// operators are only used if all terms are "name op value" filters,
+	// anything else is a single filter
+	{
+		Expression: "team=db)",
+		IsValid:    true,
+		IsMatch:    false,
+		Terms:      []string{},
+	},
+	{
+		Expression: "node | mysql",
+		IsValid:    true,
+		IsMatch:    false,
+		Terms:      []string{},
+	},
+	{
+		Expression: "(node)",
+		IsValid:    true,
+		IsMatch:    true,
+		Terms:      []string{},
+	},
+	{
+		Expression: "!node",
+		IsValid:    true,
+		IsMatch:    false,
+		Terms:      []string{},
+	},
+	{
+		Expression: "@silence_comment=foo (bar)",
+		IsValid:    true,
+		IsMatch:    false,
+		Terms:      []string{},
+	},
+	{
+		Expression: "@silence_comment=smile :)",
+		IsValid:    true,
+		IsMatch:    false,
+		Terms:      []string{},
+	},
+	{
+		Expression: "@silence_comment=~foo \\| bar=1",
+		IsValid:    true,
+		IsMatch:    false,
+		Terms:      []string{},
+	},
+	{
+		Expression: "team=db | node",
+		IsValid:    true,
+		IsMatch:    false,
+		Terms:      []string{},
+	},
+}
+
+func TestExpressionFilters(t *testing.T) {
+	for _, ft := range expressionTests {
+		f := filters.NewFilter(ft.Expression)
+		if f.GetIsValid() != ft.IsValid {
+			t.Errorf("[%s] GetIsValid() returned %#v while %#v was expected", ft.Expression, f.GetIsValid(), ft.IsValid)
+		}
+		if f.GetRawText() != ft.Expression {
+			t.Errorf("[%s] GetRawText() returned %#v != %s passed as the expression", ft.Expression, f.GetRawText(), ft.Expression)
+		}
+
+		terms := []string{}
+		for _, term := range f.GetTerms() {
+			terms = append(terms, term.GetRawText())
+		}
+		if len(terms) != len(ft.Terms) {
+			t.Errorf("[%s] GetTerms() returned %v while %v was expected", ft.Expression, terms, ft.Terms)
+			continue
+		}
+		for i := range terms {
+			if terms[i] != ft.Terms[i] {
+				t.Errorf("[%s] GetTerms() returned %v while %v was expected", ft.Expression, terms, ft.Terms)
+			}
+		}
+
+		if !f.GetIsValid() {
+			continue
+		}
+		alert := expressionAlert
+		if m := f.Match(&alert, 0); m != ft.IsMatch {
+			t.Errorf("[%s] Match() returned %#v while %#v was expected", ft.Expression, m, ft.IsMatch)
+		}
+		if ft.IsMatch && f.GetHits() != 1 {
+			t.Errorf("[%s] GetHits() returned %#v after match, expected 1", ft.Expression, f.GetHits())
+		}
+		for i, term := range f.GetTerms() {
+			if term.GetHits() != ft.TermHits[i] {
+				t.Errorf("[%s] term '%s' has %d hits, expected %d", ft.Expression, term.GetRawText(), term.GetHits(), ft.TermHits[i])
+			}
+		}
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	cache "github.com/patrickmn/go-cache"
 )
 
@@ -16,6 +18,7 @@ type matcherT interface {
 	setOperator(operator string)
 	GetOperator() string
 	Compare(valA, valB interface{}) bool
+	Validate(value string) error
 }
 
 type abstractMatcher struct {
@@ -27,6 +30,12 @@ func (matcher *abstractMatcher) GetOperator() string {
 	return matcher.Operator
 }
 
+// Validate checks if the value passed in the filter can be used by this
+// matcher, only regex matchers need to do anything here
+func (matcher *abstractMatcher) Validate(value string) error {
+	return nil
+}
+
 type equalMatcher struct {
 	abstractMatcher
 }
@@ -43,28 +52,100 @@ func (matcher *notEqualMatcher) Compare(valA, valB interface{}) bool {
 	return valA != valB
 }
 
-type moreThanMatcher struct {
-	abstractMatcher
+// parseVersion returns parsed version if given value looks like a version,
+// like v2, 1.10 or 1.2.3, plain numbers and values with leading zeros, like
+// 0.05, are not treated as versions
+func parseVersion(value string) (*semver.Version, bool) {
+	if !strings.HasPrefix(value, "v") && !strings.Contains(value, ".") {
+		return nil, false
+	}
+	core := strings.SplitN(strings.SplitN(strings.TrimPrefix(value, "v"), "-", 2)[0], "+", 2)[0]
+	for _, part := range strings.Split(core, ".") {
+		if len(part) > 1 && strings.HasPrefix(part, "0") {
+			return nil, false
+		}
+	}
+	version, err := semver.NewVersion(value)
+	if err != nil {
+		return nil, false
+	}
+	return version, true
 }
 
-func (matcher *moreThanMatcher) Compare(valA, valB interface{}) bool {
+// compareValues returns -1 if valA is lower than valB, 0 if both are equal
+// and 1 if valA is greater than valB, values are compared as numbers if both
+// are integers, as semantic versions if both look like versions, as floats if
+// both are numbers and as strings otherwise, second value returned is false
+// if any value is empty
+// Versions are checked before floats, so 1.10 is greater than 1.9
+func compareValues(valA, valB interface{}) (int, bool) {
 	if valA == nil || valA == "" || valB == nil || valB == "" {
-		return false
+		return 0, false
 	}
 
 	if intA, ok := valA.(int); ok {
 		if intB, ok := valB.(int); ok {
-			return intA > intB
+			switch {
+			case intA < intB:
+				return -1, true
+			case intA > intB:
+				return 1, true
+			}
+			return 0, true
 		}
 	}
 
-	if atoiA, err := strconv.Atoi(valA.(string)); err == nil {
-		if atoiB, err := strconv.Atoi(valB.(string)); err == nil {
-			return atoiA > atoiB
+	strA, strB := fmt.Sprintf("%v", valA), fmt.Sprintf("%v", valB)
+
+	if atoiA, err := strconv.Atoi(strA); err == nil {
+		if atoiB, err := strconv.Atoi(strB); err == nil {
+			switch {
+			case atoiA < atoiB:
+				return -1, true
+			case atoiA > atoiB:
+				return 1, true
+			}
+			return 0, true
 		}
 	}
 
-	return string(valA.(string)) > string(valB.(string))
+	if versionA, ok := parseVersion(strA); ok {
+		if versionB, ok := parseVersion(strB); ok {
+			return versionA.Compare(versionB), true
+		}
+	}
+
+	if floatA, err := strconv.ParseFloat(strA, 64); err == nil {
+		if floatB, err := strconv.ParseFloat(strB, 64); err == nil {
+			switch {
+			case floatA < floatB:
+				return -1, true
+			case floatA > floatB:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	return strings.Compare(strA, strB), true
+}
+
+type moreThanMatcher struct {
+	abstractMatcher
+}
+
+func (matcher *moreThanMatcher) Compare(valA, valB interface{}) bool {
+	result, ok := compareValues(valA, valB)
+	return ok && result > 0
+}
+
+type moreThanOrEqualMatcher struct {
+	abstractMatcher
+}
+
+func (matcher *moreThanOrEqualMatcher) Compare(valA, valB interface{}) bool {
+	result, ok := compareValues(valA, valB)
+	return ok && result >= 0
 }
 
 type lessThanMatcher struct {
@@ -72,40 +153,49 @@ type lessThanMatcher struct {
 }
 
 func (matcher *lessThanMatcher) Compare(valA, valB interface{}) bool {
-	if valA == nil || valA == "" || valB == nil || valB == "" {
-		return false
-	}
-
-	if intA, ok := valA.(int); ok {
-		if intB, ok := valB.(int); ok {
-			return intA < intB
-		}
-	}
+	result, ok := compareValues(valA, valB)
+	return ok && result < 0
+}
 
-	if atoiA, err := strconv.Atoi(valA.(string)); err == nil {
-		if atoiB, err := strconv.Atoi(valB.(string)); err == nil {
-			return atoiA < atoiB
-		}
-	}
+type lessThanOrEqualMatcher struct {
+	abstractMatcher
+}
 
-	return string(valA.(string)) < string(valB.(string))
+func (matcher *lessThanOrEqualMatcher) Compare(valA, valB interface{}) bool {
+	result, ok := compareValues(valA, valB)
+	return ok && result <= 0
 }
 
 type regexpMatcher struct {
 	abstractMatcher
 }
 
-func (matcher *regexpMatcher) Compare(valA, valB interface{}) bool {
-	r, found := matchCache.Get(valB.(string))
+// compileRegex returns compiled regex for given pattern, compiled regexes are
+// cached for a minute
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	r, found := matchCache.Get(pattern)
 	if !found {
 		var err error
-		r, err = regexp.Compile("(?i)" + valB.(string))
+		r, err = regexp.Compile(pattern)
 		if err != nil {
-			return false
+			return nil, err
 		}
-		matchCache.Set(valB.(string), r, 1*time.Minute)
+		matchCache.Set(pattern, r, 1*time.Minute)
 	}
-	return r.(*regexp.Regexp).MatchString(valA.(string))
+	return r.(*regexp.Regexp), nil
+}
+
+func (matcher *regexpMatcher) Compare(valA, valB interface{}) bool {
+	r, err := compileRegex("(?i)" + valB.(string))
+	if err != nil {
+		return false
+	}
+	return r.MatchString(valA.(string))
+}
+
+func (matcher *regexpMatcher) Validate(value string) error {
+	_, err := compileRegex("(?i)" + value)
+	return err
 }
 
 type negativeRegexMatcher struct {
@@ -117,6 +207,42 @@ func (matcher *negativeRegexMatcher) Compare(valA, valB interface{}) bool {
 	return !r.Compare(valA, valB)
 }
 
+func (matcher *negativeRegexMatcher) Validate(value string) error {
+	r := regexpMatcher{}
+	return r.Validate(value)
+}
+
+type anchoredRegexpMatcher struct {
+	abstractMatcher
+}
+
+func (matcher *anchoredRegexpMatcher) Compare(valA, valB interface{}) bool {
+	r, err := compileRegex("^(?:" + valB.(string) + ")$")
+	if err != nil {
+		return false
+	}
+	return r.MatchString(valA.(string))
+}
+
+func (matcher *anchoredRegexpMatcher) Validate(value string) error {
+	_, err := compileRegex("^(?:" + value + ")$")
+	return err
+}
+
+type negativeAnchoredRegexMatcher struct {
+	abstractMatcher
+}
+
+func (matcher *negativeAnchoredRegexMatcher) Compare(valA, valB interface{}) bool {
+	r := anchoredRegexpMatcher{}
+	return !r.Compare(valA, valB)
+}
+
+func (matcher *negativeAnchoredRegexMatcher) Validate(value string) error {
+	r := anchoredRegexpMatcher{}
+	return r.Validate(value)
+}
+
 func newMatcher(matchType string) (matcherT, error) {
 	if m, found := matcherConfig[matchType]; found {
 		return m, nil
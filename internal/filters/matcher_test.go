@@ -64,6 +64,19 @@ func TestMoreThanMatcher(t *testing.T) {
 		{"a", "a", true, false},
 		{"a", "b", true, false},
 		{"", "", true, false},
+		{"0.95", "0.5", true, true},
+		{"0.5", "0.95", true, false},
+		{"0.5", "0.05", true, true},
+		{"1.5e1", "1.25", true, true},
+		// values with a single dot are compared as versions
+		{"1.5", "1.25", true, false},
+		{"1.9", "1.10", true, false},
+		{"1.2", "1.10", true, false},
+		{"1.10", "1.9", true, true},
+		{"1.9.2", "1.10", true, false},
+		{"1.10.0", "1.9", true, true},
+		{"v2.0.0", "v1.10.3", true, true},
+		{"1.0.0-rc.1", "1.0.0", true, false},
 	}
 	for _, mt := range tests {
 		m := moreThanMatcher{}
@@ -85,6 +98,19 @@ func TestLessThanMatcher(t *testing.T) {
 		{"a", "a", true, false},
 		{"a", "b", true, true},
 		{"", "", true, false},
+		{"0.95", "0.5", true, false},
+		{"0.5", "0.95", true, true},
+		{"0.5", "0.05", true, false},
+		{"1.5e1", "1.25", true, false},
+		// values with a single dot are compared as versions
+		{"1.5", "1.25", true, true},
+		{"1.9", "1.10", true, true},
+		{"1.2", "1.10", true, true},
+		{"1.10", "1.9", true, false},
+		{"1.9.2", "1.10", true, true},
+		{"1.10.0", "1.9", true, false},
+		{"v2.0.0", "v1.10.3", true, false},
+		{"1.0.0-rc.1", "1.0.0", true, true},
 	}
 	for _, mt := range tests {
 		m := lessThanMatcher{}
@@ -94,6 +120,52 @@ func TestLessThanMatcher(t *testing.T) {
 	}
 }
 
+func TestMoreThanOrEqualMatcher(t *testing.T) {
+	tests := []matchTest{
+		{10, 1, true, true},
+		{8, 8, true, true},
+		{4, 9, true, false},
+		{"8", "8", true, true},
+		{"0.50", "0.50", true, true},
+		{"0.4", "0.5", true, false},
+		{"0.05", "0.5", true, false},
+		{"1.10", "1.9", true, true},
+		{"1.10.0", "1.10", true, true},
+		{"1.9.2", "1.10", true, false},
+		{"a", "a", true, true},
+		{"", "", true, false},
+	}
+	for _, mt := range tests {
+		m := moreThanOrEqualMatcher{}
+		if result := m.Compare(mt.ValA, mt.ValB); result != mt.Expacted {
+			t.Errorf("MoreThanOrEqualMatcher(%#v, %#v) returned %v when %v was expected", mt.ValA, mt.ValB, result, mt.Expacted)
+		}
+	}
+}
+
+func TestLessThanOrEqualMatcher(t *testing.T) {
+	tests := []matchTest{
+		{10, 1, true, false},
+		{8, 8, true, true},
+		{4, 9, true, true},
+		{"8", "8", true, true},
+		{"0.50", "0.50", true, true},
+		{"0.4", "0.5", true, true},
+		{"0.05", "0.5", true, true},
+		{"1.9", "1.10", true, true},
+		{"1.10.0", "1.10", true, true},
+		{"1.10.1", "1.10", true, false},
+		{"a", "a", true, true},
+		{"", "", true, false},
+	}
+	for _, mt := range tests {
+		m := lessThanOrEqualMatcher{}
+		if result := m.Compare(mt.ValA, mt.ValB); result != mt.Expacted {
+			t.Errorf("LessThanOrEqualMatcher(%#v, %#v) returned %v when %v was expected", mt.ValA, mt.ValB, result, mt.Expacted)
+		}
+	}
+}
+
 func TestRegexpMatcher(t *testing.T) {
 	tests := []matchTest{
 		{"abcdef", "^abc", true, true},
@@ -134,13 +206,56 @@ func TestNegativeRegexpMatcher(t *testing.T) {
 	}
 }
 
+func TestAnchoredRegexpMatcher(t *testing.T) {
+	tests := []matchTest{
+		{"abc", "abc", true, true},
+		{"abc", "a.+", true, true},
+		{"abc", "ab|abc", true, true},
+		{"abcdef", "abc", true, false},
+		{"xxabcxx", "abc", true, false},
+		{"ABC", "abc", true, false},
+		{"ABC", "(?i)abc", true, true},
+		{"xx", "^[-xxx****", false, false},
+	}
+	for _, mt := range tests {
+		m := anchoredRegexpMatcher{}
+		if result := m.Compare(mt.ValA, mt.ValB); result != mt.Expacted {
+			t.Errorf("AnchoredRegexpMatcher(%#v, %#v) returned %v when %v was expected", mt.ValA, mt.ValB, result, mt.Expacted)
+		}
+	}
+}
+
+func TestNegativeAnchoredRegexpMatcher(t *testing.T) {
+	tests := []matchTest{
+		{"abc", "abc", true, false},
+		{"abc", "a.+", true, false},
+		{"abc", "ab|abc", true, false},
+		{"abcdef", "abc", true, true},
+		{"xxabcxx", "abc", true, true},
+		{"ABC", "abc", true, true},
+		{"ABC", "(?i)abc", true, false},
+		{"xx", "^[-xxx****", false, true},
+	}
+	for _, mt := range tests {
+		m := negativeAnchoredRegexMatcher{}
+		if result := m.Compare(mt.ValA, mt.ValB); result != mt.Expacted {
+			t.Errorf("NegativeAnchoredRegexMatcher(%#v, %#v) returned %v when %v was expected", mt.ValA, mt.ValB, result, mt.Expacted)
+		}
+	}
+}
+
 func TestNewMatcher(t *testing.T) {
 	operators := []string{
 		equalOperator,
 		notEqualOperator,
 		moreThanOperator,
 		lessThanOperator,
+		moreThanOrEqualOperator,
+		lessThanOrEqualOperator,
 		regexpOperator,
+		negativeRegexOperator,
+		anchoredRegexpOperator,
+		negativeAnchoredRegexOperator,
 	}
 	for _, operator := range operators {
 		m, err := newMatcher(operator)
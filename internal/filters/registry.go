@@ -3,12 +3,18 @@ package filters
 import "regexp"
 
 const (
-	equalOperator         string = "="
-	notEqualOperator      string = "!="
-	moreThanOperator      string = ">"
-	lessThanOperator      string = "<"
-	regexpOperator        string = "=~"
-	negativeRegexOperator string = "!~"
+	equalOperator           string = "="
+	notEqualOperator        string = "!="
+	moreThanOperator        string = ">"
+	lessThanOperator        string = "<"
+	moreThanOrEqualOperator string = ">="
+	lessThanOrEqualOperator string = "<="
+	regexpOperator          string = "=~"
+	negativeRegexOperator   string = "!~"
+	// anchored regex operators are case sensitive and must match the entire
+	// value, same as regex matchers in Alertmanager silences
+	anchoredRegexpOperator        string = "==~"
+	negativeAnchoredRegexOperator string = "!=~"
 )
 
 // this needs to be hand crafted because any of the supported operator chars
@@ -17,16 +23,21 @@ const (
 // a===b should yield an error
 var matcherRegex = "[=!<>~]+"
 
-// same as matcherRegex but for the filter name part
-var filterRegex = "^(@)?[a-zA-Z_][a-zA-Z0-9_]*"
+// same as matcherRegex but for the filter name part, names can have a single
+// dot separated suffix, like @annotation.summary
+var filterRegex = "^(@)?[a-zA-Z_][a-zA-Z0-9_]*(\\.[a-zA-Z_][a-zA-Z0-9_]*)?"
 
 var matcherConfig = map[string]matcherT{
-	equalOperator:         &equalMatcher{abstractMatcher{Operator: equalOperator}},
-	notEqualOperator:      &notEqualMatcher{abstractMatcher{Operator: notEqualOperator}},
-	moreThanOperator:      &moreThanMatcher{abstractMatcher{Operator: moreThanOperator}},
-	lessThanOperator:      &lessThanMatcher{abstractMatcher{Operator: lessThanOperator}},
-	regexpOperator:        &regexpMatcher{abstractMatcher{Operator: regexpOperator}},
-	negativeRegexOperator: &negativeRegexMatcher{abstractMatcher{Operator: negativeRegexOperator}},
+	equalOperator:                 &equalMatcher{abstractMatcher{Operator: equalOperator}},
+	notEqualOperator:              &notEqualMatcher{abstractMatcher{Operator: notEqualOperator}},
+	moreThanOperator:              &moreThanMatcher{abstractMatcher{Operator: moreThanOperator}},
+	lessThanOperator:              &lessThanMatcher{abstractMatcher{Operator: lessThanOperator}},
+	moreThanOrEqualOperator:       &moreThanOrEqualMatcher{abstractMatcher{Operator: moreThanOrEqualOperator}},
+	lessThanOrEqualOperator:       &lessThanOrEqualMatcher{abstractMatcher{Operator: lessThanOrEqualOperator}},
+	regexpOperator:                &regexpMatcher{abstractMatcher{Operator: regexpOperator}},
+	negativeRegexOperator:         &negativeRegexMatcher{abstractMatcher{Operator: negativeRegexOperator}},
+	anchoredRegexpOperator:        &anchoredRegexpMatcher{abstractMatcher{Operator: anchoredRegexpOperator}},
+	negativeAnchoredRegexOperator: &negativeAnchoredRegexMatcher{abstractMatcher{Operator: negativeAnchoredRegexOperator}},
 }
 
 type filterConfig struct {
@@ -43,10 +54,17 @@ var AllFilters = []filterConfig{
 	{
 		Label:              "@alertmanager",
 		LabelRe:            regexp.MustCompile("^@alertmanager$"),
-		SupportedOperators: []string{regexpOperator, negativeRegexOperator, equalOperator, notEqualOperator},
+		SupportedOperators: []string{regexpOperator, negativeRegexOperator, anchoredRegexpOperator, negativeAnchoredRegexOperator, equalOperator, notEqualOperator},
 		Factory:            newAlertmanagerInstanceFilter,
 		Autocomplete:       alertmanagerInstanceAutocomplete,
 	},
+	{
+		Label:              "@cluster",
+		LabelRe:            regexp.MustCompile("^@cluster$"),
+		SupportedOperators: []string{regexpOperator, negativeRegexOperator, anchoredRegexpOperator, negativeAnchoredRegexOperator, equalOperator, notEqualOperator},
+		Factory:            newClusterFilter,
+		Autocomplete:       clusterAutocomplete,
+	},
 	{
 		Label:              "@state",
 		LabelRe:            regexp.MustCompile("^@state$"),
@@ -57,17 +75,37 @@ var AllFilters = []filterConfig{
 	{
 		Label:              "@receiver",
 		LabelRe:            regexp.MustCompile("^@receiver$"),
-		SupportedOperators: []string{regexpOperator, negativeRegexOperator, equalOperator, notEqualOperator},
+		SupportedOperators: []string{regexpOperator, negativeRegexOperator, anchoredRegexpOperator, negativeAnchoredRegexOperator, equalOperator, notEqualOperator},
 		Factory:            newreceiverFilter,
 		Autocomplete:       receiverAutocomplete,
 	},
+	{
+		Label:              "@inhibited",
+		LabelRe:            regexp.MustCompile("^@inhibited$"),
+		SupportedOperators: []string{equalOperator, notEqualOperator},
+		Factory:            newInhibitedFilter,
+		Autocomplete:       inhibitedAutocomplete,
+	},
+	{
+		Label:              "@inhibited_by",
+		LabelRe:            regexp.MustCompile("^@inhibited_by$"),
+		SupportedOperators: []string{equalOperator, notEqualOperator},
+		Factory:            newInhibitedByFilter,
+		Autocomplete:       inhibitedByAutocomplete,
+	},
 	{
 		Label:              "@age",
 		LabelRe:            regexp.MustCompile("^@age$"),
-		SupportedOperators: []string{lessThanOperator, moreThanOperator},
+		SupportedOperators: []string{lessThanOperator, moreThanOperator, lessThanOrEqualOperator, moreThanOrEqualOperator},
 		Factory:            newAgeFilter,
 		Autocomplete:       ageAutocomplete,
 	},
+	{
+		Label:              "@started",
+		LabelRe:            regexp.MustCompile("^@started$"),
+		SupportedOperators: []string{lessThanOperator, moreThanOperator, lessThanOrEqualOperator, moreThanOrEqualOperator},
+		Factory:            newStartedFilter,
+	},
 	{
 		Label:              "@silence_id",
 		LabelRe:            regexp.MustCompile("^@silence_id$"),
@@ -78,17 +116,51 @@ var AllFilters = []filterConfig{
 	{
 		Label:              "@silence_jira",
 		LabelRe:            regexp.MustCompile("^@silence_jira$"),
-		SupportedOperators: []string{regexpOperator, negativeRegexOperator, equalOperator, notEqualOperator},
+		SupportedOperators: []string{regexpOperator, negativeRegexOperator, anchoredRegexpOperator, negativeAnchoredRegexOperator, equalOperator, notEqualOperator},
 		Factory:            newSilenceJiraFilter,
 		Autocomplete:       silenceJiraIDAutocomplete,
 	},
 	{
 		Label:              "@silence_author",
 		LabelRe:            regexp.MustCompile("^@silence_author$"),
-		SupportedOperators: []string{regexpOperator, negativeRegexOperator, equalOperator, notEqualOperator},
+		SupportedOperators: []string{regexpOperator, negativeRegexOperator, anchoredRegexpOperator, negativeAnchoredRegexOperator, equalOperator, notEqualOperator},
 		Factory:            newSilenceAuthorFilter,
 		Autocomplete:       silenceAuthorAutocomplete,
 	},
+	{
+		Label:              "@silence_comment",
+		LabelRe:            regexp.MustCompile("^@silence_comment$"),
+		SupportedOperators: []string{regexpOperator, negativeRegexOperator, anchoredRegexpOperator, negativeAnchoredRegexOperator, equalOperator, notEqualOperator},
+		Factory:            newSilenceCommentFilter,
+	},
+	{
+		Label:              "@silence_expires",
+		LabelRe:            regexp.MustCompile("^@silence_expires$"),
+		SupportedOperators: []string{lessThanOperator, moreThanOperator, lessThanOrEqualOperator, moreThanOrEqualOperator},
+		Factory:            newSilenceExpiresFilter,
+		Autocomplete:       silenceDurationAutocomplete,
+	},
+	{
+		Label:              "@silence_age",
+		LabelRe:            regexp.MustCompile("^@silence_age$"),
+		SupportedOperators: []string{lessThanOperator, moreThanOperator, lessThanOrEqualOperator, moreThanOrEqualOperator},
+		Factory:            newSilenceAgeFilter,
+		Autocomplete:       silenceDurationAutocomplete,
+	},
+	{
+		Label:              "@has",
+		LabelRe:            regexp.MustCompile("^@has$"),
+		SupportedOperators: []string{equalOperator},
+		Factory:            newHasLabelFilter,
+		Autocomplete:       labelPresenceAutocomplete,
+	},
+	{
+		Label:              "@missing",
+		LabelRe:            regexp.MustCompile("^@missing$"),
+		SupportedOperators: []string{equalOperator},
+		Factory:            newMissingLabelFilter,
+		Autocomplete:       labelPresenceAutocomplete,
+	},
 	{
 		Label:              "@limit",
 		LabelRe:            regexp.MustCompile("^@limit$"),
@@ -96,10 +168,38 @@ var AllFilters = []filterConfig{
 		Factory:            newLimitFilter,
 		Autocomplete:       limitAutocomplete,
 	},
+	{
+		Label:              "@group_limit",
+		LabelRe:            regexp.MustCompile("^@group_limit$"),
+		SupportedOperators: []string{equalOperator},
+		Factory:            newGroupLimitFilter,
+		Autocomplete:       limitAutocomplete,
+	},
+	{
+		Label:              "@group_size",
+		LabelRe:            regexp.MustCompile("^@group_size$"),
+		SupportedOperators: []string{equalOperator, notEqualOperator, lessThanOperator, moreThanOperator, lessThanOrEqualOperator, moreThanOrEqualOperator},
+		Factory:            newGroupSizeFilter,
+		Autocomplete:       groupSizeAutocomplete,
+	},
+	{
+		Label:              "@group_state",
+		LabelRe:            regexp.MustCompile("^@group_state$"),
+		SupportedOperators: []string{equalOperator, notEqualOperator},
+		Factory:            newGroupStateFilter,
+		Autocomplete:       stateAutocomplete,
+	},
+	{
+		Label:              "@annotation.[a-zA-Z_][a-zA-Z0-9_]*",
+		LabelRe:            regexp.MustCompile("^@annotation\\.[a-zA-Z_][a-zA-Z0-9_]*$"),
+		SupportedOperators: []string{regexpOperator, negativeRegexOperator, anchoredRegexpOperator, negativeAnchoredRegexOperator, equalOperator, notEqualOperator},
+		Factory:            newAnnotationFilter,
+		Autocomplete:       annotationAutocomplete,
+	},
 	{
 		Label:              "[a-zA-Z_][a-zA-Z0-9_]*",
 		LabelRe:            regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$"),
-		SupportedOperators: []string{regexpOperator, negativeRegexOperator, equalOperator, notEqualOperator, lessThanOperator, moreThanOperator},
+		SupportedOperators: []string{regexpOperator, negativeRegexOperator, anchoredRegexpOperator, negativeAnchoredRegexOperator, equalOperator, notEqualOperator, lessThanOperator, moreThanOperator, lessThanOrEqualOperator, moreThanOrEqualOperator},
 		Factory:            newLabelFilter,
 		Autocomplete:       labelAutocomplete,
 	},
@@ -23,11 +23,20 @@ type Mapper interface {
 	IsOpenAPI() bool
 }
 
+// AlertFilter holds filters that are passed to the Alertmanager API when
+// collecting alerts, so it only returns alerts karma will use
+type AlertFilter struct {
+	// regex matching receivers to collect alerts for, empty means all
+	Receiver string
+	// list of label matchers, like foo="bar" or foo=~"ba.+"
+	Matchers []string
+}
+
 // AlertMapper handles mapping of Alertmanager alert information to karma AlertGroup models
 type AlertMapper interface {
 	Mapper
 	Decode(io.ReadCloser) ([]models.AlertGroup, error)
-	Collect(string, map[string]string, time.Duration, http.RoundTripper) ([]models.AlertGroup, error)
+	Collect(string, map[string]string, time.Duration, http.RoundTripper, AlertFilter) ([]models.AlertGroup, error)
 }
 
 // SilenceMapper handles mapping of Alertmanager silence information to karma Silence models
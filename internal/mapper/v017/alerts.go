@@ -40,7 +40,7 @@ func (m AlertMapper) IsOpenAPI() bool {
 	return true
 }
 
-func (m AlertMapper) Collect(uri string, headers map[string]string, timeout time.Duration, httpTransport http.RoundTripper) ([]models.AlertGroup, error) {
+func (m AlertMapper) Collect(uri string, headers map[string]string, timeout time.Duration, httpTransport http.RoundTripper, filter mapper.AlertFilter) ([]models.AlertGroup, error) {
 	c := newClient(uri, headers, httpTransport)
-	return groups(c, timeout)
+	return groups(c, timeout, filter)
 }
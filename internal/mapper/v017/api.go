@@ -38,11 +38,21 @@ func newClient(uri string, headers map[string]string, httpTransport http.RoundTr
 	return c
 }
 
-// Alerts will fetch all alert groups from the API
-func groups(c *client.Alertmanager, timeout time.Duration) ([]models.AlertGroup, error) {
+// Alerts will fetch all alert groups from the API, receiver regex and label
+// matchers from the filter are passed to the API so it can skip alerts we
+// don't need
+func groups(c *client.Alertmanager, timeout time.Duration, filter mapper.AlertFilter) ([]models.AlertGroup, error) {
 	ret := []models.AlertGroup{}
 
-	groups, err := c.Alertgroup.GetAlertGroups(alertgroup.NewGetAlertGroupsParamsWithTimeout(timeout))
+	params := alertgroup.NewGetAlertGroupsParamsWithTimeout(timeout)
+	if filter.Receiver != "" {
+		params.SetReceiver(&filter.Receiver)
+	}
+	if len(filter.Matchers) > 0 {
+		params.SetFilter(filter.Matchers)
+	}
+
+	groups, err := c.Alertgroup.GetAlertGroups(params)
 	if err != nil {
 		return []models.AlertGroup{}, err
 	}
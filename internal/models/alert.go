@@ -40,6 +40,9 @@ type Alert struct {
 	GeneratorURL string   `json:"-" hash:"-"`
 	SilencedBy   []string `json:"-" hash:"-"`
 	InhibitedBy  []string `json:"-" hash:"-"`
+	// Fingerprint is the fingerprint Alertmanager uses for this alert, it's
+	// computed from all labels and used to resolve InhibitedBy values
+	Fingerprint string `json:"-" hash:"-"`
 	// karma fields
 	Alertmanager []AlertmanagerInstance `json:"alertmanager"`
 	Receiver     string                 `json:"receiver"`
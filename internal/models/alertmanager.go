@@ -5,8 +5,9 @@ import "time"
 // AlertmanagerInstance describes the Alertmanager instance alert was collected
 // from
 type AlertmanagerInstance struct {
-	Name    string `json:"name"`
-	Cluster string `json:"cluster"`
+	Name        string `json:"name"`
+	Cluster     string `json:"cluster"`
+	ClusterName string `json:"clusterName"`
 	// per instance alert state
 	State string `json:"state"`
 	// timestamp collected from this instance, those on the alert itself
@@ -20,6 +21,11 @@ type AlertmanagerInstance struct {
 	// export list of silenced IDs in api response
 	SilencedBy  []string `json:"silencedBy"`
 	InhibitedBy []string `json:"inhibitedBy"`
+	// stale is true if this instance failed to respond and we're using data
+	// from the last successful pull, LastPull is only set for stale instances
+	// so it doesn't affect alert fingerprints
+	Stale    bool      `json:"stale"`
+	LastPull time.Time `json:"lastPull"`
 }
 
 // AlertmanagerAPIStatus describes the Alertmanager instance overall health
@@ -34,6 +40,24 @@ type AlertmanagerAPIStatus struct {
 	Version        string            `json:"version"`
 	Cluster        string            `json:"cluster"`
 	ClusterMembers []string          `json:"clusterMembers"`
+	// how was the version detected and the error if detection failed
+	VersionSource     string `json:"versionSource"`
+	VersionProbeError string `json:"versionProbeError"`
+	// stale is true if last pull failed but we still serve data collected
+	// from this instance during the last successful pull
+	Stale          bool                 `json:"stale"`
+	LastPull       time.Time            `json:"lastPull"`
+	CircuitBreaker CircuitBreakerStatus `json:"circuitBreaker"`
+}
+
+// CircuitBreakerStatus describes the state of the circuit breaker used when
+// pulling from the Alertmanager instance
+type CircuitBreakerStatus struct {
+	// closed, open or half-open
+	State               string `json:"state"`
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+	// when will we try to pull again, only set if breaker isn't closed
+	RetryAt time.Time `json:"retryAt"`
 }
 
 // AlertmanagerAPICounters returns number of Alertmanager instances in each
@@ -50,3 +74,22 @@ type AlertmanagerAPISummary struct {
 	Instances []AlertmanagerAPIStatus `json:"instances"`
 	Clusters  map[string][]string     `json:"clusters"`
 }
+
+// UpstreamHealth describes the state of a single Alertmanager upstream as
+// reported by karma health and readiness endpoints
+type UpstreamHealth struct {
+	Name string `json:"name"`
+	// pending, healthy, stale or failed
+	State string `json:"state"`
+	// zero value if we never had a successful pull
+	LastPull time.Time `json:"lastPull"`
+	Error    string    `json:"error"`
+}
+
+// HealthResponse is the structure of JSON response returned by karma health
+// and readiness endpoints
+type HealthResponse struct {
+	Status    string           `json:"status"`
+	Message   string           `json:"message"`
+	Upstreams []UpstreamHealth `json:"upstreams"`
+}
@@ -9,14 +9,24 @@ import (
 	"github.com/prymitive/karma/internal/slices"
 )
 
+// FilterError explains why given filter is invalid
+type FilterError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // Filter holds returned data on any filter passed by the user as part of the query
 type Filter struct {
-	Text    string `json:"text"`
-	Name    string `json:"name"`
-	Matcher string `json:"matcher"`
-	Value   string `json:"value"`
-	Hits    int    `json:"hits"`
-	IsValid bool   `json:"isValid"`
+	Text    string       `json:"text"`
+	Name    string       `json:"name"`
+	Matcher string       `json:"matcher"`
+	Value   string       `json:"value"`
+	Hits    int          `json:"hits"`
+	IsValid bool         `json:"isValid"`
+	Error   *FilterError `json:"error"`
+	// filters combined using "|", "!" and parentheses will list every
+	// filter used in the expression here
+	Terms []Filter `json:"terms"`
 }
 
 // Color is used by karmaLabelColor to reprenset colors as RGBA
@@ -99,6 +109,9 @@ type APIAlertGroupSharedMaps struct {
 type APIAlertGroup struct {
 	AlertGroup
 	Shared APIAlertGroupSharedMaps `json:"shared"`
+	// number of alerts matching all filters, it can be higher than the number
+	// of alerts in the group if @group_limit filter was used
+	TotalAlerts int `json:"totalAlerts"`
 }
 
 func (ag *APIAlertGroup) dedupLabels() {
@@ -263,6 +276,15 @@ type GridSettings struct {
 	Label   string `json:"label"`
 }
 
+// View is a named list of filters with sort settings that can be selected
+// using the view query argument
+type View struct {
+	Name    string       `json:"name"`
+	Title   string       `json:"title"`
+	Filters []string     `json:"filters"`
+	Sorting GridSettings `json:"sorting"`
+}
+
 // SortSettings nests all settings specific to sorting
 type SortSettings struct {
 	Grid         GridSettings                 `json:"grid"`
@@ -295,14 +317,23 @@ type AlertsResponse struct {
 	Version     string                        `json:"version"`
 	Upstreams   AlertmanagerAPISummary        `json:"upstreams"`
 	Silences    map[string]map[string]Silence `json:"silences"`
+	Inhibitors  map[string]map[string]string  `json:"inhibitors"`
 	AlertGroups []APIAlertGroup               `json:"groups"`
 	TotalAlerts int                           `json:"totalAlerts"`
+	TotalGroups int                           `json:"totalGroups"`
 	Colors      LabelsColorMap                `json:"colors"`
 	Filters     []Filter                      `json:"filters"`
 	Counters    LabelNameStatsList            `json:"counters"`
 	Settings    Settings                      `json:"settings"`
 }
 
+// ValidateResponse is the structure of JSON response returned when validating
+// filters
+type ValidateResponse struct {
+	Filters []Filter `json:"filters"`
+	IsValid bool     `json:"isValid"`
+}
+
 // Autocomplete is the structure of autocomplete object for filter hints
 // this is internal representation, not what's returned to the user
 type Autocomplete struct {
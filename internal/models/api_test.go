@@ -100,6 +100,7 @@ func TestDedupSharedMaps(t *testing.T) {
         {
           "name": "",
           "cluster": "fakeCluster",
+          "clusterName": "",
           "state": "",
           "startsAt": "0001-01-01T00:00:00Z",
           "source": "",
@@ -107,11 +108,14 @@ func TestDedupSharedMaps(t *testing.T) {
             "fakeSilence1",
             "fakeSilence2"
           ],
-          "inhibitedBy": null
+          "inhibitedBy": null,
+          "stale": false,
+          "lastPull": "0001-01-01T00:00:00Z"
         },
         {
           "name": "",
           "cluster": "fakeCluster",
+          "clusterName": "",
           "state": "",
           "startsAt": "0001-01-01T00:00:00Z",
           "source": "",
@@ -119,7 +123,9 @@ func TestDedupSharedMaps(t *testing.T) {
             "fakeSilence1",
             "fakeSilence2"
           ],
-          "inhibitedBy": null
+          "inhibitedBy": null,
+          "stale": false,
+          "lastPull": "0001-01-01T00:00:00Z"
         }
       ],
       "receiver": ""
@@ -136,6 +142,7 @@ func TestDedupSharedMaps(t *testing.T) {
         {
           "name": "",
           "cluster": "fakeCluster",
+          "clusterName": "",
           "state": "",
           "startsAt": "0001-01-01T00:00:00Z",
           "source": "",
@@ -143,11 +150,14 @@ func TestDedupSharedMaps(t *testing.T) {
             "fakeSilence1",
             "fakeSilence2"
           ],
-          "inhibitedBy": null
+          "inhibitedBy": null,
+          "stale": false,
+          "lastPull": "0001-01-01T00:00:00Z"
         },
         {
           "name": "",
           "cluster": "fakeCluster",
+          "clusterName": "",
           "state": "",
           "startsAt": "0001-01-01T00:00:00Z",
           "source": "",
@@ -155,7 +165,9 @@ func TestDedupSharedMaps(t *testing.T) {
             "fakeSilence1",
             "fakeSilence2"
           ],
-          "inhibitedBy": null
+          "inhibitedBy": null,
+          "stale": false,
+          "lastPull": "0001-01-01T00:00:00Z"
         }
       ],
       "receiver": ""
@@ -172,6 +184,7 @@ func TestDedupSharedMaps(t *testing.T) {
         {
           "name": "",
           "cluster": "fakeCluster",
+          "clusterName": "",
           "state": "",
           "startsAt": "0001-01-01T00:00:00Z",
           "source": "",
@@ -179,11 +192,14 @@ func TestDedupSharedMaps(t *testing.T) {
             "fakeSilence1",
             "fakeSilence2"
           ],
-          "inhibitedBy": null
+          "inhibitedBy": null,
+          "stale": false,
+          "lastPull": "0001-01-01T00:00:00Z"
         },
         {
           "name": "",
           "cluster": "fakeCluster",
+          "clusterName": "",
           "state": "",
           "startsAt": "0001-01-01T00:00:00Z",
           "source": "",
@@ -191,7 +207,9 @@ func TestDedupSharedMaps(t *testing.T) {
             "fakeSilence1",
             "fakeSilence2"
           ],
-          "inhibitedBy": null
+          "inhibitedBy": null,
+          "stale": false,
+          "lastPull": "0001-01-01T00:00:00Z"
         }
       ],
       "receiver": ""
@@ -217,7 +235,8 @@ func TestDedupSharedMaps(t *testing.T) {
         "fakeSilence2"
       ]
     }
-  }
+  },
+  "totalAlerts": 0
 }`
 
 	agJSON, _ := json.MarshalIndent(ag, "", "  ")
@@ -66,10 +66,12 @@ func parseCustomColor(colorStore models.LabelsColorMap, key, val, customColor st
 // from label key and value passed here
 // It's used to generate unique colors for configured labels
 func ColorLabel(colorStore models.LabelsColorMap, key string, val string) {
+	colorRules := config.Config.LabelColors()
+
 	// first handle custom colors
-	_, ok := config.Config.Labels.Color.Custom[key]
+	_, ok := colorRules.Custom[key]
 	if ok {
-		for _, colorRule := range config.Config.Labels.Color.Custom[key] {
+		for _, colorRule := range colorRules.Custom[key] {
 			if colorRule.Value == val {
 				parseCustomColor(colorStore, key, val, colorRule.Color)
 				return
@@ -83,7 +85,7 @@ func ColorLabel(colorStore models.LabelsColorMap, key string, val string) {
 	}
 
 	// if no custom color is found then generate unique colors if needed
-	if slices.StringInSlice(config.Config.Labels.Color.Unique, key) {
+	if slices.StringInSlice(colorRules.Unique, key) {
 		if _, found := colorStore[key]; !found {
 			colorStore[key] = make(map[string]models.LabelColors)
 		}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sync"
 
 	"github.com/prymitive/karma/internal/models"
 )
@@ -13,11 +14,18 @@ type jiraDetectRule struct {
 	URL    string
 }
 
-var jiraDetectRules = []jiraDetectRule{}
+var (
+	jiraDetectRules = []jiraDetectRule{}
+	// jiraLock protects jiraDetectRules since those can be replaced when
+	// configuration is reloaded
+	jiraLock = sync.RWMutex{}
+)
 
 // ParseRules will parse and validate list of JIRA detection rules provided
-// from config, valid rules will be stored for future use in DetectJIRAs() calls
+// from config, valid rules will replace all previously parsed rules and will
+// be used in future DetectJIRAs() calls
 func ParseRules(rules []models.JiraRule) {
+	parsedRules := []jiraDetectRule{}
 	for _, rule := range rules {
 		if rule.Regex == "" || rule.URI == "" {
 			log.Fatalf("Invalid JIRA rule with regexp '%s' and url '%s'", rule.Regex, rule.URI)
@@ -26,15 +34,23 @@ func ParseRules(rules []models.JiraRule) {
 			Regexp: regexp.MustCompile(rule.Regex),
 			URL:    rule.URI,
 		}
-		jiraDetectRules = append(jiraDetectRules, jdr)
+		parsedRules = append(parsedRules, jdr)
 	}
+
+	jiraLock.Lock()
+	jiraDetectRules = parsedRules
+	jiraLock.Unlock()
 }
 
 // DetectJIRAs will try to find JIRA links in Alertmanager silence objects
 // using regexp rules from configuration that were parsed and populated
 // by ParseRules call
 func DetectJIRAs(silence *models.Silence) (jiraID, jiraLink string) {
-	for _, jdr := range jiraDetectRules {
+	jiraLock.RLock()
+	rules := jiraDetectRules
+	jiraLock.RUnlock()
+
+	for _, jdr := range rules {
 		jiraID := jdr.Regexp.FindString(silence.Comment)
 		if jiraID != "" {
 			jiraLink := fmt.Sprintf("%s/browse/%s", jdr.URL, jiraID)
@@ -1,6 +1,8 @@
 package verprobe
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
 
 	"github.com/prometheus/common/expfmt"
@@ -13,6 +15,19 @@ const (
 	versionLabel    = "version"
 )
 
+type versionInfo struct {
+	Version string `json:"version"`
+}
+
+// statusResponse handles both /api/v1/status and /api/v2/status responses,
+// v1 API wraps everything in data while v2 returns versionInfo at top level
+type statusResponse struct {
+	VersionInfo versionInfo `json:"versionInfo"`
+	Data        struct {
+		VersionInfo versionInfo `json:"versionInfo"`
+	} `json:"data"`
+}
+
 // Detect alertmanager version by reading metrics it exposes
 func Detect(r io.Reader) (string, error) {
 	parser := expfmt.TextParser{}
@@ -37,5 +52,30 @@ func Detect(r io.Reader) (string, error) {
 		}
 	}
 
+	if version == "" {
+		return "", errors.New("no version information in metrics")
+	}
+
+	return version, nil
+}
+
+// DetectFromStatus detects alertmanager version by reading the response of
+// status API endpoint, both v1 and v2 APIs are supported
+func DetectFromStatus(r io.Reader) (string, error) {
+	resp := statusResponse{}
+	err := json.NewDecoder(r).Decode(&resp)
+	if err != nil {
+		return "", err
+	}
+
+	version := resp.VersionInfo.Version
+	if version == "" {
+		version = resp.Data.VersionInfo.Version
+	}
+	if version == "" {
+		return "", errors.New("no version information in status API response")
+	}
+
+	log.Infof("Upstream version: %s", version)
 	return version, nil
 }
@@ -0,0 +1,72 @@
+package verprobe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prymitive/karma/internal/verprobe"
+)
+
+type detectTest struct {
+	body    string
+	version string
+	isError bool
+}
+
+var detectTests = []detectTest{
+	{
+		body:    "alertmanager_build_info{branch=\"HEAD\",goversion=\"go1.13.1\",revision=\"abc\",version=\"0.19.0\"} 1\n",
+		version: "0.19.0",
+	},
+	{
+		body:    "go_goroutines 35\n",
+		isError: true,
+	},
+	{
+		body:    "{{{",
+		isError: true,
+	},
+}
+
+func TestDetect(t *testing.T) {
+	for _, testCase := range detectTests {
+		version, err := verprobe.Detect(strings.NewReader(testCase.body))
+		if (err != nil) != testCase.isError {
+			t.Errorf("Detect(%q) returned error=%v, expected error=%v", testCase.body, err, testCase.isError)
+		}
+		if version != testCase.version {
+			t.Errorf("Detect(%q) returned version %q, expected %q", testCase.body, version, testCase.version)
+		}
+	}
+}
+
+var detectFromStatusTests = []detectTest{
+	{
+		body:    `{"cluster":{"name":"foo"},"versionInfo":{"version":"0.19.0"}}`,
+		version: "0.19.0",
+	},
+	{
+		body:    `{"status":"success","data":{"versionInfo":{"version":"0.15.3"}}}`,
+		version: "0.15.3",
+	},
+	{
+		body:    `{"status":"success","data":{}}`,
+		isError: true,
+	},
+	{
+		body:    "alertmanager_build_info 1",
+		isError: true,
+	},
+}
+
+func TestDetectFromStatus(t *testing.T) {
+	for _, testCase := range detectFromStatusTests {
+		version, err := verprobe.DetectFromStatus(strings.NewReader(testCase.body))
+		if (err != nil) != testCase.isError {
+			t.Errorf("DetectFromStatus(%q) returned error=%v, expected error=%v", testCase.body, err, testCase.isError)
+		}
+		if version != testCase.version {
+			t.Errorf("DetectFromStatus(%q) returned version %q, expected %q", testCase.body, version, testCase.version)
+		}
+	}
+}
@@ -54,7 +54,12 @@ const GroupFooter = observer(
               value={am}
             />
           ))}
-          <FilteringLabel name={StaticLabels.Receiver} value={group.receiver} />
+          {group.receiver ? (
+            <FilteringLabel
+              name={StaticLabels.Receiver}
+              value={group.receiver}
+            />
+          ) : null}
           {group.shared.annotations
             .filter(a => a.isLink === true)
             .map(a => (
@@ -76,6 +76,12 @@ describe("<GroupFooter />", () => {
     expect(toDiffableHtml(tree.html())).toMatchSnapshot();
   });
 
+  it("doesn't render @receiver label if group receiver is empty", () => {
+    group.receiver = "";
+    const tree = MountedGroupFooter().find("GroupFooter");
+    expect(tree.html()).not.toMatch(/@receiver/);
+  });
+
   it("render deduplicated silence if present", () => {
     for (const id of Object.keys(group.alerts)) {
       group.alerts[id].alertmanager[0].silencedBy = ["123456789"];
@@ -44,9 +44,12 @@ const MenuContent = onClickOutside(
     let groupFilters = Object.keys(group.labels).map(name =>
       FormatQuery(name, QueryOperators.Equal, group.labels[name])
     );
-    groupFilters.push(
-      FormatQuery(StaticLabels.Receiver, QueryOperators.Equal, group.receiver)
-    );
+    // receiver is empty if alerts were regrouped and use multiple receivers
+    if (group.receiver) {
+      groupFilters.push(
+        FormatQuery(StaticLabels.Receiver, QueryOperators.Equal, group.receiver)
+      );
+    }
     const baseURL = [
       window.location.protocol,
       "//",
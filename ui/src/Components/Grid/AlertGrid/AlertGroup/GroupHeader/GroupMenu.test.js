@@ -90,6 +90,17 @@ describe("<MenuContent />", () => {
     expect(copy).toHaveBeenCalledTimes(1);
   });
 
+  it("copied link doesn't include @receiver filter if group receiver is empty", () => {
+    const group = MockAlertGroup({ alertname: "Fake Alert" }, [], [], {}, {});
+    group.receiver = "";
+    const tree = MountedMenuContent(group);
+    const button = tree.find(".dropdown-item").at(0);
+    button.simulate("click");
+    const link = copy.mock.calls[copy.mock.calls.length - 1][0];
+    expect(link).toMatch(/alertname/);
+    expect(link).not.toMatch(/receiver/);
+  });
+
   it("clicking on 'Silence' icon opens the silence form modal", () => {
     const group = MockAlertGroup({ alertname: "Fake Alert" }, [], [], {}, {});
     const tree = MountedMenuContent(group);
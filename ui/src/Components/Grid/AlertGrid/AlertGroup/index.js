@@ -236,7 +236,9 @@ const AlertGroup = observer(
                           showAlertmanagers={
                             showAlertmanagers && !showAlertmanagersInFooter
                           }
-                          showReceiver={group.alerts.length === 1}
+                          showReceiver={
+                            group.alerts.length === 1 || !group.receiver
+                          }
                           afterUpdate={afterUpdate}
                           alertStore={alertStore}
                           silenceFormStore={silenceFormStore}
@@ -115,6 +115,16 @@ describe("<AlertGroup />", () => {
     expect(footer.html()).not.toMatch(/@alertmanager/);
   });
 
+  it("renders @receiver label on every alert if group receiver is empty", () => {
+    MockAlerts(2);
+    group.receiver = "";
+    const tree = MountedAlertGroup(jest.fn(), false);
+    expect(
+      tree.find("Alert").find("FilteringLabel[name='@receiver']")
+    ).toHaveLength(2);
+    expect(tree.find("GroupFooter").html()).not.toMatch(/@receiver/);
+  });
+
   it("only renders titlebar when collapsed", () => {
     MockAlerts(10);
     const tree = MountedAlertGroup(jest.fn(), false);
@@ -99,6 +99,20 @@ const Help = () => (
             False if compared alert attribute value matches <code>value</code>{" "}
             regex.
           </FilterOperatorHelp>
+          <FilterOperatorHelp
+            operator="==~"
+            description="Anchored regular expression match"
+          >
+            True if the whole compared alert attribute value matches{" "}
+            <code>value</code> regex, match is case sensitive.
+          </FilterOperatorHelp>
+          <FilterOperatorHelp
+            operator="!=~"
+            description="Negative anchored regular expression match"
+          >
+            False if the whole compared alert attribute value matches{" "}
+            <code>value</code> regex, match is case sensitive.
+          </FilterOperatorHelp>
           <FilterOperatorHelp operator="&gt;" description="Greater than match">
             True if compared alert attribute value is greater than{" "}
             <code>value</code>.
@@ -107,6 +121,20 @@ const Help = () => (
             True if compared alert attribue value is less than{" "}
             <code>value</code>.
           </FilterOperatorHelp>
+          <FilterOperatorHelp
+            operator="&gt;="
+            description="Greater than or equal match"
+          >
+            True if compared alert attribute value is greater than or equal
+            to <code>value</code>.
+          </FilterOperatorHelp>
+          <FilterOperatorHelp
+            operator="&lt;="
+            description="Less than or equal match"
+          >
+            True if compared alert attribute value is less than or equal to{" "}
+            <code>value</code>.
+          </FilterOperatorHelp>
         </dl>
       }
       extraProps={{ open: true }}
@@ -117,7 +145,7 @@ const Help = () => (
         <dl>
           <QueryHelp
             title="Match alerts based on any label"
-            operators={["=", "!=", "=~", "!~", ">", "<"]}
+            operators={["=", "!=", "=~", "!~", ">", "<", ">=", "<="]}
           >
             <FilterExample example="alertname=UnableToPing">
               Match alerts with label <code>alertname</code> equal to{" "}
@@ -141,8 +169,15 @@ const Help = () => (
             </FilterExample>
             <FilterExample example="priority>4">
               Match alerts with label <code>priority</code> value{" "}
-              <code>&gt;</code> than <code>4</code>. Value will be casted to
-              integer if possible, string comparision will be used as fallback.
+              <code>&gt;</code> than <code>4</code>. Numbers and semantic
+              versions are compared by value, string comparision will be used
+              as fallback. Values with dots, like 1.10, are compared as
+              versions, unless any part has a leading zero, like 0.05.
+            </FilterExample>
+            <FilterExample example="version&gt;=1.10">
+              Match alerts with label <code>version</code> value greater than or
+              equal to <code>1.10</code>, so <code>1.10.2</code> will match
+              but <code>1.9.5</code> will not.
             </FilterExample>
           </QueryHelp>
         </dl>
@@ -171,6 +206,42 @@ const Help = () => (
             </FilterExample>
           </QueryHelp>
 
+          <QueryHelp
+            title="Match alerts based on annotations"
+            operators={["=", "!=", "=~", "!~"]}
+          >
+            <FilterExample example="@annotation.owner=team-db">
+              Match alerts with annotation <code>owner</code> equal to{" "}
+              <code>team-db</code>.
+            </FilterExample>
+            <FilterExample example="@annotation.owner!=team-db">
+              Match alerts with annotation <code>owner</code> missing or not
+              equal to <code>team-db</code>.
+            </FilterExample>
+            <FilterExample example="@annotation.dashboard=~grafana">
+              Match alerts with annotation <code>dashboard</code> matching
+              regular expression <code>/.*grafana.*/</code>.
+            </FilterExample>
+          </QueryHelp>
+
+          <QueryHelp
+            title="Match alerts based on the Alertmanager cluster name"
+            operators={["=", "!=", "=~", "!~"]}
+          >
+            <FilterExample example="@cluster=prod">
+              Match alerts collected from the <code>prod</code> Alertmanager
+              cluster.
+            </FilterExample>
+            <FilterExample example="@cluster!=prod">
+              Match alerts collected from any Alertmanager cluster except{" "}
+              <code>prod</code>.
+            </FilterExample>
+            <FilterExample example="@cluster=~eu">
+              Match alerts collected from any Alertmanager cluster with name
+              matching regular expression <code>/.*eu.*/</code>.
+            </FilterExample>
+          </QueryHelp>
+
           <QueryHelp
             title="Match alerts based on the receiver name"
             operators={["=", "!=", "=~", "!~"]}
@@ -219,6 +290,32 @@ const Help = () => (
             </FilterExample>
           </QueryHelp>
 
+          <QueryHelp
+            title="Match alerts based on the inhibition state"
+            operators={["=", "!="]}
+          >
+            <FilterExample example="@inhibited=true">
+              Match alerts suppressed by an inhibition rule.
+            </FilterExample>
+            <FilterExample example="@inhibited=false">
+              Match alerts not suppressed by any inhibition rule.
+            </FilterExample>
+          </QueryHelp>
+
+          <QueryHelp
+            title="Match inhibited alerts based on the fingerprint of the inhibiting alert"
+            operators={["=", "!="]}
+          >
+            <FilterExample example="@inhibited_by=5ab2c9ec3a0f0e7b">
+              Match alerts inhibited by alert with fingerprint{" "}
+              <code>5ab2c9ec3a0f0e7b</code>.
+            </FilterExample>
+            <FilterExample example="@inhibited_by!=5ab2c9ec3a0f0e7b">
+              Match inhibited alerts except those inhibited by alert with
+              fingerprint <code>5ab2c9ec3a0f0e7b</code>.
+            </FilterExample>
+          </QueryHelp>
+
           <QueryHelp
             title="Match alerts based on the author of silence"
             operators={["=", "!=", "=~", "!~"]}
@@ -256,19 +353,105 @@ const Help = () => (
             </FilterExample>
           </QueryHelp>
 
+          <QueryHelp
+            title="Match alerts based on the silence comment"
+            operators={["=", "!=", "=~", "!~"]}
+          >
+            <FilterExample example="@silence_comment=~maintenance">
+              Match silenced alerts where silence comment matches regular
+              expression <code>/.*maintenance.*/</code>.
+            </FilterExample>
+            <FilterExample example="@silence_comment!~maintenance">
+              Match alerts that are not silenced or where silence comment does
+              not match regular expression <code>/.*maintenance.*/</code>.
+            </FilterExample>
+          </QueryHelp>
+
+          <QueryHelp
+            title="Match alerts based on the silence expiry time"
+            operators={[">", "<", ">=", "<="]}
+          >
+            <FilterExample example="@silence_expires&lt;1h">
+              Match silenced alerts where silence expires in less than 1 hour.
+            </FilterExample>
+            <FilterExample example="@silence_expires&gt;1d">
+              Match silenced alerts where silence expires in more than 1 day.
+            </FilterExample>
+          </QueryHelp>
+
+          <QueryHelp
+            title="Match alerts based on the silence age"
+            operators={[">", "<", ">=", "<="]}
+          >
+            <FilterExample example="@silence_age&gt;1d">
+              Match silenced alerts where silence started more than 1 day ago.
+            </FilterExample>
+            <FilterExample example="@silence_age&lt;15m">
+              Match silenced alerts where silence started less than 15 minutes
+              ago.
+            </FilterExample>
+          </QueryHelp>
+
+          <QueryHelp
+            title="Match alerts with given label set"
+            operators={["="]}
+          >
+            <FilterExample example="@has=team">
+              Match alerts with the <code>team</code> label, even if the label
+              value is empty.
+            </FilterExample>
+          </QueryHelp>
+          <QueryHelp
+            title="Match alerts without given label"
+            operators={["="]}
+          >
+            <FilterExample example="@missing=team">
+              Match alerts without the <code>team</code> label.
+            </FilterExample>
+          </QueryHelp>
           <QueryHelp
             title="Limit number of displayed alerts"
             operators={["="]}
             warning="Value must be a number &gt;= 1."
           >
             <FilterExample example="@limit=10">
-              Limit number of displayed alerts to 10.
+              Limit number of displayed alerts to 10, limit is applied after
+              all other filters, including filters matching alert groups.
+            </FilterExample>
+          </QueryHelp>
+          <QueryHelp
+            title="Limit number of displayed alerts per group"
+            operators={["="]}
+            warning="Value must be a number &gt;= 1."
+          >
+            <FilterExample example="@group_limit=5">
+              Limit number of displayed alerts in each group to 5, total number
+              of alerts is still reported.
+            </FilterExample>
+          </QueryHelp>
+          <QueryHelp
+            title="Match alert groups based on the number of alerts"
+            operators={["=", "!=", ">", "<", ">=", "<="]}
+          >
+            <FilterExample example="@group_size&gt;20">
+              Match alert groups with more than 20 alerts.
+            </FilterExample>
+          </QueryHelp>
+          <QueryHelp
+            title="Match alert groups based on the state"
+            operators={["=", "!="]}
+          >
+            <FilterExample example="@group_state=active">
+              Match alert groups with at least one active alert.
+            </FilterExample>
+            <FilterExample example="@group_state=suppressed">
+              Match alert groups where all alerts are suppressed.
             </FilterExample>
           </QueryHelp>
 
           <QueryHelp
             title="Match alerts based on creation timestamp"
-            operators={[">", "<"]}
+            operators={[">", "<", ">=", "<="]}
           >
             <FilterExample example="@age&gt;15m">
               Match alerts older than 15 minutes.
@@ -279,6 +462,50 @@ const Help = () => (
             <FilterExample example="@age&lt;10h30m">
               Match alerts more recent than 10 hours and 30 minutes.
             </FilterExample>
+            <FilterExample example="@age&gt;2d">
+              Match alerts older than 2 days.
+            </FilterExample>
+          </QueryHelp>
+          <QueryHelp
+            title="Match alerts based on absolute start time"
+            operators={[">", "<", ">=", "<="]}
+          >
+            <FilterExample example="@started&gt;2019-05-01T10:00Z">
+              Match alerts that started after 10:00 UTC on 1st of May 2019.
+            </FilterExample>
+            <FilterExample example="@started&lt;2019-05-01T10:30:00+02:00">
+              Match alerts that started before 10:30 CEST on 1st of May 2019.
+            </FilterExample>
+          </QueryHelp>
+        </dl>
+      }
+    />
+    <Accordion
+      text="Combining filters"
+      content={
+        <dl>
+          <QueryHelp
+            title="Combine multiple filters into a single expression"
+            operators={["|", "!", "(", ")"]}
+            warning="Filters separated by spaces must all match. Operators need to
+            be separated from filter values with spaces, so regular expressions
+            like job=~(a|b) are not affected. If any part of the string is not a
+            filter, like plain text, the whole string is used as a single
+            filter."
+          >
+            <FilterExample example="team=db | team=storage">
+              Match alerts with label <code>team</code> equal to{" "}
+              <code>db</code> or <code>storage</code>.
+            </FilterExample>
+            <FilterExample example="!@state=suppressed">
+              Match alerts that are not suppressed.
+            </FilterExample>
+            <FilterExample
+              example="(team=db | team=storage) !@state=suppressed"
+            >
+              Match alerts with label <code>team</code> equal to{" "}
+              <code>db</code> or <code>storage</code> that are not suppressed.
+            </FilterExample>
           </QueryHelp>
         </dl>
       }